@@ -0,0 +1,28 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the 'config' command
+var (
+	ConfigCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration files",
+		Long:  "The subcommand used as the root for all commands that manage the configuration and repository mapping files",
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(ConfigCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+}
@@ -0,0 +1,273 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// initCmd represents the 'config init' command
+var (
+	initDefaultTeam    string
+	initMembers        string
+	initGitHubTeam     string
+	initPath           string
+	initForce          bool
+	initNonInteractive bool
+	initCmd            = &cobra.Command{
+		Use:   "init",
+		Short: "Creates a new configuration file",
+		Long: `Asks for the GitHub organizations to gather information from, the name of
+the default team, and the members of that team (optionally fetching the members
+of that team from a GitHub team), along with the repository mapping file to use
+(if any), then writes a new configuration file containing those values (by
+default, to the 'getGhInfo.yaml' file in the '$XDG_CONFIG_HOME' directory, or in
+the '~/.config' directory if that variable isn't set). The configuration is checked (in the
+same way as the 'config validate' command, but without using the GitHub API)
+before it is written. When the '--non-interactive' flag is used, the answers are
+taken from the command-line flags instead (for use in provisioning scripts).`,
+		Annotations: map[string]string{cmd.ConfigOptionalAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			results := initConfigFile()
+			utils.DumpMapAsJSON(results)
+			if !results["valid"].(bool) {
+				os.Exit(-10)
+			}
+		},
+	}
+)
+
+func init() {
+	cmd.ConfigCmd.AddCommand(initCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	initCmd.Flags().StringVar(&initDefaultTeam, "default-team", "", "name of the default team")
+	initCmd.Flags().StringVar(&initMembers, "members", "", "comma-separated list of GitHub IDs for the members of the default team")
+	initCmd.Flags().StringVar(&initGitHubTeam, "github-team", "", "GitHub team (org/slug) to fetch the members of the default team from")
+	initCmd.Flags().StringVarP(&cmd.RepoMappingFile, "repo-mapping-file", "m", "", "name of the repository mapping file to use by default")
+	initCmd.Flags().StringVar(&initPath, "path", "", "name of the configuration file to write (defaults to '$XDG_CONFIG_HOME/getGhInfo.yaml')")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite the configuration file if it already exists")
+	initCmd.Flags().BoolVar(&initNonInteractive, "non-interactive", false, "take the answers from the command-line flags instead of prompting for them")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("initDefaultTeam", initCmd.Flags().Lookup("default-team"))
+	viper.BindPFlag("initMembers", initCmd.Flags().Lookup("members"))
+	viper.BindPFlag("initGitHubTeam", initCmd.Flags().Lookup("github-team"))
+	viper.BindPFlag("repoMappingFile", initCmd.Flags().Lookup("repo-mapping-file"))
+	viper.BindPFlag("initPath", initCmd.Flags().Lookup("path"))
+	viper.BindPFlag("initForce", initCmd.Flags().Lookup("force"))
+	viper.BindPFlag("initNonInteractive", initCmd.Flags().Lookup("non-interactive"))
+}
+
+// the reader used to read the answers to our prompts
+var stdinReader = bufio.NewReader(os.Stdin)
+
+/*
+ * a utility function that returns the answer to the input prompt; when running
+ * interactively the prompt is written to the standard error stream and the answer
+ * read from the standard input stream (with the input default value used if the
+ * answer is blank), otherwise the default value is simply returned
+ */
+func ask(prompt string, defaultValue string) string {
+	if viper.GetBool("initNonInteractive") {
+		return defaultValue
+	}
+	if defaultValue != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", prompt, defaultValue)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", prompt)
+	}
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil && err != io.EOF {
+		fmt.Fprintf(os.Stderr, "ERROR: while reading answer; %v\n", err)
+		os.Exit(-1)
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultValue
+	}
+	return answer
+}
+
+/*
+ * a utility function that splits a comma-separated list of values, skipping any
+ * blank values
+ */
+func splitList(value string) []string {
+	valueList := []string{}
+	for _, val := range strings.Split(value, ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			valueList = append(valueList, val)
+		}
+	}
+	return valueList
+}
+
+/*
+ * a utility function that returns the name of the configuration file to write
+ */
+func getInitPath() string {
+	if initPath := viper.GetString("initPath"); initPath != "" {
+		return initPath
+	}
+	return filepath.Join(cmd.GetUserConfigDir(), "getGhInfo.yaml")
+}
+
+/*
+ * a utility function that fetches the (direct) members of the named GitHub team (of the form
+ * 'org/slug' or '@org/slug', or just 'slug' for a team in the first of the named
+ * organizations)
+ */
+func fetchGitHubTeamMembers(gitHubTeam string, orgNames []string) []map[string]string {
+	orgName, teamSlug, found := strings.Cut(strings.TrimPrefix(gitHubTeam, "@"), "/")
+	if !found {
+		if len(orgNames) == 0 {
+			fmt.Fprintf(os.Stderr, "ERROR: no organization found for the GitHub team '%s'; use the form 'org/slug'\n", gitHubTeam)
+			os.Exit(-6)
+		}
+		orgName, teamSlug = orgNames[0], orgName
+	}
+	if os.Getenv("GITHUB_TOKEN") == "" {
+		fmt.Fprintf(os.Stderr, "ERROR: the GITHUB_TOKEN environment variable must be set to fetch the members of a GitHub team\n")
+		os.Exit(-1)
+	}
+	fmt.Fprintf(os.Stderr, "INFO: fetching the members of the GitHub team '%s/%s'\n", orgName, teamSlug)
+	return utils.GetGitHubTeamMembers(utils.GetAuthenticatedClient(), orgName, teamSlug, true)
+}
+
+/*
+ * a utility function that combines the members fetched from GitHub with the members
+ * listed by GitHub ID, listing each GitHub ID only once (and making sure that each
+ * member has a unique user name, falling back to their GitHub ID if necessary)
+ */
+func getInitTeamMembers(fetchedMembers []map[string]string, gitHubIds []string) []map[string]string {
+	for _, gitHubId := range gitHubIds {
+		fetchedMembers = append(fetchedMembers, map[string]string{"user": gitHubId, "githubid": gitHubId})
+	}
+	teamMembers := []map[string]string{}
+	seenIds := []string{}
+	seenUsers := []string{}
+	for _, member := range fetchedMembers {
+		if utils.SliceContains(seenIds, strings.ToLower(member["githubid"])) {
+			continue
+		}
+		if utils.SliceContains(seenUsers, member["user"]) {
+			member["user"] = member["githubid"]
+		}
+		seenIds = append(seenIds, strings.ToLower(member["githubid"]))
+		seenUsers = append(seenUsers, member["user"])
+		teamMembers = append(teamMembers, member)
+	}
+	return teamMembers
+}
+
+/*
+ * a function that formats the answers as a configuration file
+ */
+func configAsYAML(orgNames []string, teamName string, teamMembers []map[string]string, repoMappingFile string) string {
+	var buf bytes.Buffer
+	buf.WriteString("# generated using the 'getGhInfo config init' command\n")
+	buf.WriteString("orgs:\n")
+	for _, orgName := range orgNames {
+		fmt.Fprintf(&buf, "  - %s\n", utils.YamlScalar(orgName))
+	}
+	buf.WriteString("teams:\n")
+	fmt.Fprintf(&buf, "  %s:\n", utils.YamlScalar(teamName))
+	for _, member := range teamMembers {
+		fmt.Fprintf(&buf, "    - %s\n", utils.TeamMemberAsYAML(member))
+	}
+	fmt.Fprintf(&buf, "default_team: %s\n", utils.YamlScalar(teamName))
+	if repoMappingFile != "" {
+		fmt.Fprintf(&buf, "default_repo_mapping: %s\n", utils.YamlScalar(repoMappingFile))
+	}
+	return buf.String()
+}
+
+/*
+ * define the function that is used to gather the answers, check the resulting
+ * configuration, and write it out to the configuration file
+ */
+func initConfigFile() map[string]interface{} {
+	configFile := getInitPath()
+	// make sure that we won't overwrite an existing configuration file by accident
+	if _, err := os.Stat(configFile); err == nil && !viper.GetBool("initForce") {
+		if viper.GetBool("initNonInteractive") || !strings.HasPrefix(strings.ToLower(ask(fmt.Sprintf("The file '%s' already exists; overwrite it? (y/N)", configFile), "")), "y") {
+			fmt.Fprintf(os.Stderr, "ERROR: the file '%s' already exists; use the '--force' flag to overwrite it\n", configFile)
+			os.Exit(-1)
+		}
+	}
+	// gather the answers (using the values passed in on the command-line as the defaults)
+	orgNames := splitList(ask("GitHub organizations to gather information from (comma-separated)", viper.GetString("orgList")))
+	teamName := ask("Name of the default team", viper.GetString("initDefaultTeam"))
+	gitHubTeam := ask("GitHub team to fetch the members of that team from (org/slug, or blank to skip)", viper.GetString("initGitHubTeam"))
+	fetchedMembers := []map[string]string{}
+	if gitHubTeam != "" {
+		fetchedMembers = fetchGitHubTeamMembers(gitHubTeam, orgNames)
+	}
+	gitHubIds := splitList(ask("GitHub IDs of the (other) members of that team (comma-separated)", viper.GetString("initMembers")))
+	repoMappingFile := ask("Repository mapping file to use by default (or blank to skip)", viper.GetString("repoMappingFile"))
+	if len(orgNames) == 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: at least one GitHub organization is required; use the '-o, --org-list' flag\n")
+		os.Exit(-1)
+	}
+	if teamName == "" {
+		fmt.Fprintf(os.Stderr, "ERROR: the name of the default team is required; use the '--default-team' flag\n")
+		os.Exit(-4)
+	}
+	teamMembers := getInitTeamMembers(fetchedMembers, gitHubIds)
+	if len(teamMembers) == 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: the default team must have at least one member; use the '--members' or '--github-team' flags\n")
+		os.Exit(-2)
+	}
+	// then check the resulting configuration before writing it out
+	configText := configAsYAML(orgNames, teamName, teamMembers, repoMappingFile)
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(configText)); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: while reading the generated configuration; %v\n", err)
+		os.Exit(-10)
+	}
+	v := newConfigValidator()
+	v.validateConfig(configFile)
+	if repoMappingFile != "" {
+		if _, err := os.Stat(repoMappingFile); err != nil {
+			v.addProblem("warning", configFile, "default_repo_mapping", "unable to find the repository mapping file '%s'", repoMappingFile)
+		} else {
+			v.validateRepoMapping(repoMappingFile)
+		}
+	}
+	if v.numErrors == 0 {
+		if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: while creating the directory for '%s'; %v\n", configFile, err)
+			os.Exit(-1)
+		}
+		if err := ioutil.WriteFile(configFile, []byte(configText), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: while writing '%s'; %v\n", configFile, err)
+			os.Exit(-1)
+		}
+		fmt.Fprintf(os.Stderr, "INFO: wrote the configuration file '%s'\n", configFile)
+	}
+	// and return the results
+	return map[string]interface{}{"title": "Configuration Initialization",
+		"configFile": configFile, "written": v.numErrors == 0, "orgs": orgNames, "defaultTeam": teamName,
+		"numMembers": len(teamMembers), "repoMappingFile": repoMappingFile,
+		"valid": v.numErrors == 0, "numErrors": v.numErrors, "numWarnings": len(v.problems) - v.numErrors,
+		"problems": v.problems}
+}
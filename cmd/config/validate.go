@@ -0,0 +1,590 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// validateCmd represents the 'validate' command
+var (
+	validateOffline bool
+	validateCmd     = &cobra.Command{
+		Use:   "validate",
+		Short: "Validates the configuration and repository mapping files",
+		Long: `Checks the structure of the configuration file and of the repository mapping
+file, looking for unknown teams, duplicate users, team members without a GitHub
+ID, mapping groups without any repositories, and values that can't be parsed;
+unless the '--offline' flag is used, every GitHub login, organization, and
+repository URL in those files is also resolved using the GitHub API. All of the
+problems that are found are reported at once, and the command exits with a
+non-zero status if any errors were found.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			results := validate()
+			utils.DumpMapAsJSON(results)
+			if !results["valid"].(bool) {
+				os.Exit(-10)
+			}
+		},
+	}
+)
+
+func init() {
+	cmd.ConfigCmd.AddCommand(validateCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	validateCmd.Flags().StringVarP(&cmd.RepoMappingFile, "repo-mapping-file", "m", "", "name of the repository mapping file to validate")
+	validateCmd.Flags().BoolVar(&validateOffline, "offline", false, "skip resolving GitHub logins, organizations, and repositories")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("repoMappingFile", validateCmd.Flags().Lookup("repo-mapping-file"))
+	viper.BindPFlag("validateOffline", validateCmd.Flags().Lookup("offline"))
+}
+
+// the keys that are expected for each team member and for each group (and repository)
+// in the repository mapping file; any other keys are reported as warnings
+var (
+	knownMemberKeys = []string{"user", "name", "githubid", "aliases", "emails", "joined", "left"}
+	memberListKeys  = []string{"aliases", "emails"}
+	knownGroupKeys  = []string{"group", "repositories", "children"}
+	knownRepoKeys   = []string{"url", "tags"}
+	repoUrlRegexp   = regexp.MustCompile(`^https://github\.com/([^/\s]+)/([^/\s]+?)/?$`)
+)
+
+/*
+ * the validator collects all of the problems found in the configuration and repository
+ * mapping files (so that they can be reported at once), along with the GitHub logins,
+ * organizations, and repositories that should be resolved using the GitHub API (and the
+ * location where each of those was first found)
+ */
+type configValidator struct {
+	problems   []map[string]interface{}
+	numErrors  int
+	logins     map[string]string
+	loginList  []string
+	orgs       map[string]string
+	orgList    []string
+	repos      map[string]string
+	repoList   []string
+	teamNames  []string
+	mappedTeam map[string]bool
+}
+
+/*
+ * a utility function that returns a new (empty) validator
+ */
+func newConfigValidator() *configValidator {
+	return &configValidator{
+		problems:   []map[string]interface{}{},
+		logins:     map[string]string{},
+		orgs:       map[string]string{},
+		repos:      map[string]string{},
+		mappedTeam: map[string]bool{},
+	}
+}
+
+/*
+ * a utility function that records a problem (with a severity of either "error" or
+ * "warning") found at the given path in the named file
+ */
+func (v *configValidator) addProblem(severity string, fileName string, path string, format string, args ...interface{}) {
+	if severity == "error" {
+		v.numErrors++
+	}
+	v.problems = append(v.problems, map[string]interface{}{
+		"severity": severity,
+		"file":     fileName,
+		"path":     path,
+		"message":  fmt.Sprintf(format, args...),
+	})
+}
+
+/*
+ * a utility function that records a value (a login, organization, or repository) that
+ * should be resolved using the GitHub API, along with where it was first found
+ */
+func addToResolve(values map[string]string, valueList []string, value string, location string) []string {
+	key := strings.ToLower(value)
+	if _, ok := values[key]; ok {
+		return valueList
+	}
+	values[key] = location
+	return append(valueList, value)
+}
+
+/*
+ * a utility function that returns the string value of a (scalar) configuration value;
+ * note that unquoted dates are parsed as timestamps, so those are converted back to dates
+ */
+func getScalarString(val interface{}) (string, bool) {
+	switch typedVal := val.(type) {
+	case string:
+		return typedVal, true
+	case time.Time:
+		return typedVal.Format("2006-01-02"), true
+	case int, int64, float64, bool:
+		return fmt.Sprint(typedVal), true
+	}
+	return "", false
+}
+
+/*
+ * a utility function that returns the sorted list of keys in a map
+ */
+func getSortedKeys(inputMap map[string]interface{}) []string {
+	keys := []string{}
+	for key := range inputMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/*
+ * a utility function that converts a map parsed from the repository mapping file (which
+ * has interface keys) into a map of strings to interfaces
+ */
+func toStringMap(val interface{}) (map[string]interface{}, bool) {
+	inputMap, ok := val.(map[interface{}]interface{})
+	if !ok {
+		return nil, false
+	}
+	outputMap := map[string]interface{}{}
+	for key, val := range inputMap {
+		outputMap[fmt.Sprint(key)] = val
+	}
+	return outputMap, true
+}
+
+/*
+ * checks the organizations, teams, and other values defined in the configuration file
+ */
+func (v *configValidator) validateConfig(fileName string) {
+	// first, check the list of organizations
+	if orgsVal := viper.Get("orgs"); orgsVal == nil {
+		v.addProblem("warning", fileName, "orgs", "no organizations defined; the '-o, --org-list' flag must be used with every command")
+	} else if orgList, ok := orgsVal.([]interface{}); !ok {
+		v.addProblem("error", fileName, "orgs", "must be a list of organization names")
+	} else {
+		for idx, org := range orgList {
+			path := fmt.Sprintf("orgs[%d]", idx)
+			if orgName, ok := org.(string); !ok || orgName == "" {
+				v.addProblem("error", fileName, path, "organization name must be a non-empty string")
+			} else {
+				v.orgList = addToResolve(v.orgs, v.orgList, orgName, path)
+			}
+		}
+	}
+	// then check the teams (and the members of each team)
+	teamsVal := viper.Get("teams")
+	teamsMap, ok := teamsVal.(map[string]interface{})
+	if teamsVal == nil {
+		v.addProblem("error", fileName, "teams", "the required 'teams' map is missing")
+	} else if !ok {
+		v.addProblem("error", fileName, "teams", "must be a map of team names to lists of members")
+	}
+	v.teamNames = getSortedKeys(teamsMap)
+	userLocations := map[string]string{}
+	userGitHubIds := map[string]string{}
+	loginUsers := map[string]string{}
+	loginOwners := map[string]string{}
+	for _, teamName := range v.teamNames {
+		teamPath := "teams." + teamName
+		memberList, ok := teamsMap[teamName].([]interface{})
+		if !ok {
+			v.addProblem("error", fileName, teamPath, "the members of team '%s' must be defined as a list", teamName)
+			continue
+		}
+		if len(memberList) == 0 {
+			v.addProblem("warning", fileName, teamPath, "team '%s' has no members", teamName)
+		}
+		teamLogins := map[string]bool{}
+		teamUsers := map[string]bool{}
+		for idx, member := range memberList {
+			path := fmt.Sprintf("%s[%d]", teamPath, idx)
+			memberMap, ok := member.(map[string]interface{})
+			if !ok {
+				v.addProblem("error", fileName, path, "team member must be a map (eg. {user: ..., name: ..., githubid: ...})")
+				continue
+			}
+			for _, key := range getSortedKeys(memberMap) {
+				if !utils.SliceContains(knownMemberKeys, key) {
+					v.addProblem("warning", fileName, path, "unrecognized key '%s'", key)
+				}
+			}
+			memberStrMap := map[string]string{}
+			memberLists := map[string][]string{}
+			for key, val := range memberMap {
+				// the 'aliases' (other GitHub logins) and 'emails' for a member are lists of values
+				if utils.SliceContains(memberListKeys, key) {
+					listVal, ok := val.([]interface{})
+					if !ok {
+						v.addProblem("error", fileName, path, "the value of the '%s' key must be a list", key)
+						continue
+					}
+					for itemIdx, item := range listVal {
+						if strItem, ok := item.(string); ok && strItem != "" {
+							memberLists[key] = append(memberLists[key], strItem)
+						} else {
+							v.addProblem("error", fileName, fmt.Sprintf("%s.%s[%d]", path, key, itemIdx), "must be a non-empty string")
+						}
+					}
+					continue
+				}
+				strVal, ok := getScalarString(val)
+				if !ok {
+					v.addProblem("error", fileName, path, "the value of the '%s' key must be a scalar value", key)
+					continue
+				}
+				memberStrMap[key] = strVal
+			}
+			// every member must have a GitHub ID (and each GitHub ID should only appear once per team)
+			gitHubId := memberStrMap["githubid"]
+			if gitHubId == "" {
+				v.addProblem("error", fileName, path, "team member is missing the required 'githubid' key")
+			} else {
+				v.loginList = addToResolve(v.logins, v.loginList, gitHubId, path)
+				if teamLogins[strings.ToLower(gitHubId)] {
+					v.addProblem("error", fileName, path, "duplicate member; GitHub ID '%s' appears more than once in team '%s'", gitHubId, teamName)
+				}
+				teamLogins[strings.ToLower(gitHubId)] = true
+				// and each of a person's GitHub logins (their GitHub ID and any aliases) can
+				// only belong to that person
+				for _, login := range append([]string{gitHubId}, memberLists["aliases"]...) {
+					if owner, ok := loginOwners[strings.ToLower(login)]; ok && !strings.EqualFold(owner, gitHubId) {
+						v.addProblem("error", fileName, path, "duplicate user; GitHub login '%s' is also used by '%s'", login, owner)
+					} else if !ok {
+						loginOwners[strings.ToLower(login)] = gitHubId
+					}
+					if login != gitHubId {
+						v.loginList = addToResolve(v.logins, v.loginList, login, path)
+					}
+				}
+			}
+			for _, email := range memberLists["emails"] {
+				if !strings.Contains(email, "@") {
+					v.addProblem("warning", fileName, path, "'%s' doesn't look like an email address", email)
+				}
+			}
+			// and the user names must map to the same GitHub ID everywhere they appear
+			user := memberStrMap["user"]
+			if user == "" {
+				v.addProblem("warning", fileName, path, "team member has no 'user' name, so it can't be selected using the '-u, --user-list' flag")
+			} else {
+				if teamUsers[user] {
+					v.addProblem("error", fileName, path, "duplicate member; user '%s' appears more than once in team '%s'", user, teamName)
+				}
+				teamUsers[user] = true
+				if prevId, ok := userGitHubIds[user]; ok && !strings.EqualFold(prevId, gitHubId) {
+					v.addProblem("error", fileName, path, "duplicate user; user '%s' is mapped to GitHub ID '%s' here, but to '%s' at %s", user, gitHubId, prevId, userLocations[user])
+				} else if !ok {
+					userGitHubIds[user] = gitHubId
+					userLocations[user] = path
+				}
+				if gitHubId != "" {
+					if prevUser, ok := loginUsers[strings.ToLower(gitHubId)]; ok && prevUser != user {
+						v.addProblem("warning", fileName, path, "GitHub ID '%s' is given the user name '%s' here, but '%s' elsewhere", gitHubId, user, prevUser)
+					} else if !ok {
+						loginUsers[strings.ToLower(gitHubId)] = user
+					}
+				}
+			}
+			// finally, check the (optional) dates that this member joined and left the team
+			dates := map[string]time.Time{}
+			for _, key := range []string{"joined", "left"} {
+				if dateStr, ok := memberStrMap[key]; ok {
+					date, err := time.Parse("2006-01-02", dateStr)
+					if err != nil {
+						v.addProblem("error", fileName, path, "unable to parse %s date '%s'; expected format is '2006-01-02'", key, dateStr)
+						continue
+					}
+					dates[key] = date
+				}
+			}
+			if joined, ok := dates["joined"]; ok {
+				if left, ok := dates["left"]; ok && left.Before(joined) {
+					v.addProblem("error", fileName, path, "the 'left' date is before the 'joined' date")
+				}
+			}
+		}
+	}
+	// then check the values that refer to the teams that we just checked
+	if defaultTeam := viper.GetString("default_team"); defaultTeam != "" {
+		if _, _, ok := utils.ParseGitHubTeamName(defaultTeam); !ok && !utils.SliceContains(v.teamNames, defaultTeam) {
+			v.addProblem("error", fileName, "default_team", "unknown team '%s'", defaultTeam)
+		}
+	}
+	if teamSlugsVal := viper.Get("team_slugs"); teamSlugsVal != nil {
+		teamSlugs, ok := teamSlugsVal.(map[string]interface{})
+		if !ok {
+			v.addProblem("error", fileName, "team_slugs", "must be a map of team names to GitHub team slugs")
+		}
+		for _, teamName := range getSortedKeys(teamSlugs) {
+			if !utils.SliceContains(v.teamNames, teamName) {
+				v.addProblem("error", fileName, "team_slugs."+teamName, "unknown team '%s'", teamName)
+			}
+			if slug, ok := teamSlugs[teamName].(string); !ok || slug == "" {
+				v.addProblem("error", fileName, "team_slugs."+teamName, "GitHub team slug must be a non-empty string")
+			}
+		}
+	}
+	// and finally, check the rest of the (scalar) values that have a restricted set of values
+	if ownershipSource := viper.GetString("ownership_source"); ownershipSource != "" && !utils.SliceContains(utils.OwnershipSources, strings.ToLower(ownershipSource)) {
+		v.addProblem("error", fileName, "ownership_source", "unrecognized ownership source '%s'; expected 'mapping', 'codeowners', or 'both'", ownershipSource)
+	}
+	if timeZoneName := viper.GetString("timezone"); timeZoneName != "" {
+		if _, err := time.LoadLocation(timeZoneName); err != nil {
+			v.addProblem("error", fileName, "timezone", "unable to load time zone '%s'; expected an IANA time zone name (eg. 'Asia/Singapore')", timeZoneName)
+		}
+	}
+	if viper.IsSet("fiscal_year_start_month") {
+		if startMonth, ok := viper.Get("fiscal_year_start_month").(int); !ok || startMonth < 1 || startMonth > 12 {
+			v.addProblem("error", fileName, "fiscal_year_start_month", "invalid value '%v'; expected a value between 1 and 12", viper.Get("fiscal_year_start_month"))
+		}
+	}
+	// the values defined for each profile are only checked when that profile is selected
+	// (using the '--profile' flag), so here we only check the structure of the profiles
+	if profilesVal := viper.Get("profiles"); profilesVal != nil {
+		profilesMap, ok := profilesVal.(map[string]interface{})
+		if !ok {
+			v.addProblem("error", fileName, "profiles", "must be a map of profile names to configuration values")
+		}
+		for _, profileName := range getSortedKeys(profilesMap) {
+			if _, ok := profilesMap[profileName].(map[string]interface{}); !ok {
+				v.addProblem("error", fileName, "profiles."+profileName, "must be a map of configuration values")
+			}
+		}
+	}
+}
+
+/*
+ * checks the structure of the repository mapping file (see the getTeamRepoMappingList
+ * function in the utils package for a description of that structure)
+ */
+func (v *configValidator) validateRepoMapping(fileName string) {
+	if fileName == "" {
+		if strings.ToLower(viper.GetString("ownership_source")) != "codeowners" {
+			v.addProblem("warning", "", "default_repo_mapping", "no repository mapping file defined; the '-m, --repo-mapping-file' flag must be used with the 'repo' commands")
+		}
+		return
+	}
+	yfile, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		v.addProblem("error", fileName, "", "unable to read the repository mapping file; %s", err)
+		return
+	}
+	var mapping interface{}
+	if err := yaml.Unmarshal(yfile, &mapping); err != nil {
+		v.addProblem("error", fileName, "", "unable to parse the repository mapping file; %s", err)
+		return
+	}
+	groupList, ok := mapping.([]interface{})
+	if !ok {
+		v.addProblem("error", fileName, "", "the repository mapping file must contain a list of groups")
+		return
+	}
+	v.validateMappingGroups(fileName, groupList, "")
+	// and look for teams that aren't mapped to any repositories
+	for _, teamName := range v.teamNames {
+		if !v.mappedTeam[teamName] {
+			v.addProblem("warning", fileName, "", "team '%s' has no group in the repository mapping file", teamName)
+		}
+	}
+}
+
+/*
+ * checks a list of groups from the repository mapping file (and, recursively,
+ * the children of each of those groups)
+ */
+func (v *configValidator) validateMappingGroups(fileName string, groupList []interface{}, pathPrefix string) {
+	for idx, group := range groupList {
+		path := fmt.Sprintf("%s[%d]", pathPrefix, idx)
+		groupMap, ok := toStringMap(group)
+		if !ok {
+			v.addProblem("error", fileName, path, "group must be a map (with 'group', 'repositories', and 'children' keys)")
+			continue
+		}
+		for _, key := range getSortedKeys(groupMap) {
+			if !utils.SliceContains(knownGroupKeys, key) {
+				v.addProblem("warning", fileName, path, "unrecognized key '%s'", key)
+			}
+		}
+		// the name of a top-level group must match one of the teams in the configuration
+		// file, while a child group that doesn't match a team is only a warning (its
+		// repositories are still rolled up into the team for its parent group)
+		groupName, ok := groupMap["group"].(string)
+		if !ok || groupName == "" {
+			v.addProblem("error", fileName, path, "group is missing the required 'group' name")
+		} else if v.mappedTeam[groupName] {
+			v.addProblem("error", fileName, path, "duplicate group; group '%s' appears more than once", groupName)
+		} else {
+			v.mappedTeam[groupName] = true
+			if !utils.SliceContains(v.teamNames, groupName) {
+				severity := "error"
+				if pathPrefix != "" {
+					severity = "warning"
+				}
+				v.addProblem(severity, fileName, path, "unknown team '%s'; the group name must match one of the teams in the configuration file", groupName)
+			}
+		}
+		// then check the repositories for this group
+		numRepos := 0
+		if reposVal, ok := groupMap["repositories"]; ok && reposVal != nil {
+			repoList, ok := reposVal.([]interface{})
+			if !ok {
+				v.addProblem("error", fileName, path+".repositories", "must be a list of repositories")
+			}
+			numRepos = len(repoList)
+			groupUrls := map[string]bool{}
+			for repoIdx, repo := range repoList {
+				repoPath := fmt.Sprintf("%s.repositories[%d]", path, repoIdx)
+				repoMap, ok := toStringMap(repo)
+				if !ok {
+					v.addProblem("error", fileName, repoPath, "repository must be a map (with 'url' and 'tags' keys)")
+					continue
+				}
+				for _, key := range getSortedKeys(repoMap) {
+					if !utils.SliceContains(knownRepoKeys, key) {
+						v.addProblem("warning", fileName, repoPath, "unrecognized key '%s'", key)
+					}
+				}
+				url, _ := repoMap["url"].(string)
+				if matches := repoUrlRegexp.FindStringSubmatch(url); matches == nil {
+					v.addProblem("error", fileName, repoPath, "invalid repository URL '%v'; expected 'https://github.com/<org>/<repo>'", repoMap["url"])
+				} else {
+					if groupUrls[strings.ToLower(url)] {
+						v.addProblem("warning", fileName, repoPath, "duplicate repository '%s' in group '%s'", url, groupName)
+					}
+					groupUrls[strings.ToLower(url)] = true
+					v.repoList = addToResolve(v.repos, v.repoList, matches[1]+"/"+matches[2], repoPath)
+				}
+				if tagsVal, ok := repoMap["tags"]; ok && tagsVal != nil {
+					tagList, ok := tagsVal.([]interface{})
+					if !ok {
+						v.addProblem("error", fileName, repoPath+".tags", "must be a list of tags")
+					}
+					for tagIdx, tag := range tagList {
+						if _, ok := getScalarString(tag); !ok {
+							v.addProblem("error", fileName, fmt.Sprintf("%s.tags[%d]", repoPath, tagIdx), "tag must be a scalar value")
+						}
+					}
+				}
+			}
+		}
+		// and the children of this group
+		numChildren := 0
+		if childrenVal, ok := groupMap["children"]; ok && childrenVal != nil {
+			childList, ok := childrenVal.([]interface{})
+			if !ok {
+				v.addProblem("error", fileName, path+".children", "must be a list of groups")
+			}
+			numChildren = len(childList)
+			v.validateMappingGroups(fileName, childList, path+".children")
+		}
+		if numRepos == 0 && numChildren == 0 {
+			v.addProblem("error", fileName, path, "group '%s' has no repositories (and no children)", groupName)
+		}
+	}
+}
+
+// the queries used to resolve the GitHub logins, organizations, and repositories
+var userLoginQuery struct {
+	User struct {
+		Login string
+	} `graphql:"user(login: $login)"`
+}
+
+var orgLoginQuery struct {
+	Organization struct {
+		Login string
+	} `graphql:"organization(login: $login)"`
+}
+
+var repositoryQuery struct {
+	Repository struct {
+		NameWithOwner string
+		IsArchived    bool
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+/*
+ * resolves each of the GitHub logins, organizations, and repositories found in the
+ * configuration and repository mapping files using the GitHub API
+ */
+func (v *configValidator) resolveWithGitHub(configFile string, repoMappingFile string) {
+	if os.Getenv("GITHUB_TOKEN") == "" {
+		v.addProblem("error", "", "", "the GITHUB_TOKEN environment variable is not set, so GitHub logins and repositories can't be resolved; use the '--offline' flag to skip these checks")
+		return
+	}
+	client := utils.GetAuthenticatedClient()
+	for _, orgName := range v.orgList {
+		vars := map[string]interface{}{"login": githubv4.String(orgName)}
+		if err := client.Query(context.Background(), &orgLoginQuery, vars); err != nil {
+			v.addProblem("error", configFile, v.orgs[strings.ToLower(orgName)], "unable to resolve GitHub organization '%s'; %v", orgName, err)
+		}
+		fmt.Fprintf(os.Stderr, ".")
+	}
+	for _, login := range v.loginList {
+		vars := map[string]interface{}{"login": githubv4.String(login)}
+		if err := client.Query(context.Background(), &userLoginQuery, vars); err != nil {
+			v.addProblem("error", configFile, v.logins[strings.ToLower(login)], "unable to resolve GitHub login '%s'; %v", login, err)
+		}
+		fmt.Fprintf(os.Stderr, ".")
+	}
+	for _, repo := range v.repoList {
+		owner, name, _ := strings.Cut(repo, "/")
+		vars := map[string]interface{}{
+			"owner": githubv4.String(owner),
+			"name":  githubv4.String(name),
+		}
+		if err := client.Query(context.Background(), &repositoryQuery, vars); err != nil {
+			v.addProblem("error", repoMappingFile, v.repos[strings.ToLower(repo)], "unable to resolve repository '%s'; %v", repo, err)
+		} else if repositoryQuery.Repository.IsArchived {
+			v.addProblem("warning", repoMappingFile, v.repos[strings.ToLower(repo)], "repository '%s' is archived", repo)
+		}
+		fmt.Fprintf(os.Stderr, ".")
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+}
+
+/*
+ * define the function that is used to validate the configuration file and the
+ * repository mapping file, returning all of the problems that were found
+ */
+func validate() map[string]interface{} {
+	v := newConfigValidator()
+	configFile := viper.ConfigFileUsed()
+	repoMappingFile := utils.GetRepoMappingFileName()
+	v.validateConfig(configFile)
+	v.validateRepoMapping(repoMappingFile)
+	offline := viper.GetBool("validateOffline")
+	if !offline {
+		v.resolveWithGitHub(configFile, repoMappingFile)
+	}
+	// and return the results
+	return map[string]interface{}{"title": "Configuration Validation",
+		"configFile": configFile, "repoMappingFile": repoMappingFile, "offline": offline,
+		"valid": v.numErrors == 0, "numErrors": v.numErrors, "numWarnings": len(v.problems) - v.numErrors,
+		"problems": v.problems}
+}
@@ -11,8 +11,11 @@ import (
 
 const (
 	// define a couple of constant variables containing formating strings
-	// (used to format dates for output and GraphQL queries)
-	ISO8601_FormatStr     = "2006-01-02T15:04:05.999Z"
+	// (used to format dates for output and GraphQL queries); note that the
+	// ISO8601 format includes the UTC offset for the time zone being used
+	// (or a 'Z' for UTC) so that search qualifiers like 'created:' and
+	// 'closed:' select the correct time window in any time zone
+	ISO8601_FormatStr     = "2006-01-02T15:04:05.999Z07:00"
 	YearMonthDayFormatStr = "2006-01-02"
 )
 
@@ -26,6 +29,10 @@ var (
 	// used in some of the issues/pulls subcommands to track a flag indicating
 	// that the output should be sorted by the time to last response (or staleness)
 	SortByStaleness bool
+	// used in the count and statistics subcommands of the issues/pulls subcommands
+	// to track a flag indicating that the results should be compared with the results
+	// from the preceding time window
+	ComparePrevious bool
 	// and the repo command itself
 	RepoCmd = &cobra.Command{
 		Use:   "repo",
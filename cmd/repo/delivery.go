@@ -0,0 +1,227 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package repo
+
+import (
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+)
+
+// DeliveryCmd represents the 'repo delivery' command
+var (
+	// used to track the source of the deployments that are counted (releases,
+	// tags, or both) and the labels that mark a change as an incident
+	deploymentSource string
+	incidentLabels   string
+	// and the delivery command itself
+	DeliveryCmd = &cobra.Command{
+		Use:   "delivery",
+		Short: "Gather delivery-related (DORA) data",
+		Long: `The subcommand used as the root for all queries for delivery-related data
+(deployment frequency, lead time for changes, and change failure rate); in
+these queries each release (or tag) published in one of the team's
+repositories is treated as a deployment`,
+	}
+)
+
+/*
+ * Define a few types that we can use to define (and extract data from) the body of the GraphQL
+ * queries that will be used to retrieve the releases and tags for a given repository
+ */
+type Release struct {
+	TagName      string
+	CreatedAt    githubv4.DateTime
+	PublishedAt  githubv4.DateTime
+	IsDraft      bool
+	IsPrerelease bool
+}
+type releasesBody struct {
+	Nodes    []Release
+	PageInfo cmd.PageInfo
+}
+
+type TagRef struct {
+	Name   string
+	Target struct {
+		Commit struct {
+			CommittedDate githubv4.DateTime
+		} `graphql:"... on Commit"`
+		Tag struct {
+			Tagger struct {
+				Date githubv4.DateTime
+			}
+			Target struct {
+				Commit struct {
+					CommittedDate githubv4.DateTime
+				} `graphql:"... on Commit"`
+			}
+		} `graphql:"... on Tag"`
+	}
+}
+type tagsBody struct {
+	Nodes    []TagRef
+	PageInfo cmd.PageInfo
+}
+
+/*
+ * define a set of structs that can be used to query GitHub for the releases and tags in
+ * a given repository (by owner and name), newest first; as with the search queries used
+ * elsewhere, the first struct in each pair is used to query for the first page of results
+ * and the second is used to query for subsequent pages of results
+ */
+var FirstReleasesQuery struct {
+	Repository struct {
+		IsPrivate  bool
+		IsArchived bool
+		Releases   struct {
+			releasesBody
+		} `graphql:"releases(first: $first, orderBy: {field: CREATED_AT, direction: DESC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+var ReleasesQuery struct {
+	Repository struct {
+		IsPrivate  bool
+		IsArchived bool
+		Releases   struct {
+			releasesBody
+		} `graphql:"releases(first: $first, after: $after, orderBy: {field: CREATED_AT, direction: DESC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+var FirstTagsQuery struct {
+	Repository struct {
+		IsPrivate  bool
+		IsArchived bool
+		Refs       struct {
+			tagsBody
+		} `graphql:"refs(refPrefix: \"refs/tags/\", first: $first, orderBy: {field: TAG_COMMIT_DATE, direction: DESC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+var TagsQuery struct {
+	Repository struct {
+		IsPrivate  bool
+		IsArchived bool
+		Refs       struct {
+			tagsBody
+		} `graphql:"refs(refPrefix: \"refs/tags/\", first: $first, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC})"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+/*
+ * define a few functions to get the values we'll need from the underlying TagRef; for
+ * annotated tags the time the tag was created (by the tagger) is used, while for lightweight
+ * tags the only time we have is the time that the tagged commit was committed
+ */
+func (t *TagRef) GetTaggedAt() githubv4.DateTime {
+	if !t.Target.Tag.Tagger.Date.IsZero() {
+		return t.Target.Tag.Tagger.Date
+	}
+	return t.Target.Commit.CommittedDate
+}
+func (t *TagRef) GetCommittedDate() githubv4.DateTime {
+	if !t.Target.Tag.Target.Commit.CommittedDate.IsZero() {
+		return t.Target.Tag.Target.Commit.CommittedDate
+	}
+	return t.Target.Commit.CommittedDate
+}
+
+/*
+ * Define the types used to retrieve the merged PRs (and the first commit made on each of
+ * them) that are used to calculate lead times and change failure rates
+ */
+type MergedPullRequest struct {
+	Title       string
+	Url         string
+	MergedAt    githubv4.DateTime
+	BaseRefName string
+	Repository  struct {
+		cmd.Repository
+		DefaultBranchRef struct {
+			Name string
+		}
+	}
+	Labels struct {
+		Nodes []struct {
+			Name string
+		}
+	} `graphql:"labels(first: 20)"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				AuthoredDate  githubv4.DateTime
+				CommittedDate githubv4.DateTime
+			}
+		}
+	} `graphql:"commits(first: 1)"`
+}
+type MergedPrSearchEdges []struct {
+	Cursor githubv4.String
+	Node   struct {
+		MergedPullRequest `graphql:"... on PullRequest"`
+	}
+}
+type mergedPrSearchBody struct {
+	IssueCount githubv4.Int
+	Edges      MergedPrSearchEdges
+	PageInfo   cmd.PageInfo
+}
+
+var FirstMergedPrSearchQuery struct {
+	Search struct {
+		mergedPrSearchBody
+	} `graphql:"search(first: $first, query: $query, type: $type)"`
+}
+
+var MergedPrSearchQuery struct {
+	Search struct {
+		mergedPrSearchBody
+	} `graphql:"search(first: $first, after: $after, query: $query, type: $type)"`
+}
+
+func init() {
+	cmd.RepoCmd.AddCommand(DeliveryCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	DeliveryCmd.PersistentFlags().StringVarP(&cmd.LookbackTime, "lookback-time", "l", "", "'lookback' time window (eg. 10d, 3w, 2m, 1q, 1y)")
+	DeliveryCmd.PersistentFlags().StringVarP(&cmd.ReferenceDate, "ref-date", "d", "", "reference date for time window (YYYY-MM-DD)")
+	DeliveryCmd.PersistentFlags().BoolVarP(&cmd.CompleteWeeks, "complete-weeks", "w", false, "only output complete weeks (starting Monday)")
+	DeliveryCmd.PersistentFlags().StringVar(&cmd.SinceDate, "since", "", "start date for time window (YYYY-MM-DD)")
+	DeliveryCmd.PersistentFlags().StringVar(&cmd.UntilDate, "until", "", "end date (inclusive) for time window (YYYY-MM-DD)")
+	DeliveryCmd.PersistentFlags().StringVar(&cmd.Period, "period", "", "named time window (eg. 2026-Q3, 2026-09, 2026-W37, last-quarter)")
+	DeliveryCmd.PersistentFlags().StringVarP(&cmd.CompTeam, "team", "t", "", "name of team (or comma-separated list of teams) to restrict repository list to")
+	DeliveryCmd.PersistentFlags().BoolVar(&cmd.AllTeams, "all-teams", false, "gather data for each of the teams defined in the configuration file")
+	DeliveryCmd.PersistentFlags().StringVarP(&cmd.RepoMappingFile, "repo-mapping-file", "m", "", "name of the repository mapping file to use")
+	DeliveryCmd.PersistentFlags().StringVar(&cmd.RepoTags, "repo-tags", "", "only include repositories with one of these (comma-separated) tags")
+	DeliveryCmd.PersistentFlags().StringVar(&cmd.ExcludeRepoTags, "exclude-repo-tags", "", "exclude repositories with any of these (comma-separated) tags")
+	DeliveryCmd.PersistentFlags().StringVar(&cmd.GroupBy, "group-by", "", "report separately for each repository group (currently only 'tag')")
+	DeliveryCmd.PersistentFlags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
+	DeliveryCmd.PersistentFlags().StringVar(&deploymentSource, "deployment-source", "", "what to count as a deployment (releases, tags, or both)")
+	DeliveryCmd.PersistentFlags().StringVar(&incidentLabels, "incident-labels", "", "comma-separated list of labels that mark an incident")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("lookbackTime", DeliveryCmd.PersistentFlags().Lookup("lookback-time"))
+	viper.BindPFlag("referenceDate", DeliveryCmd.PersistentFlags().Lookup("ref-date"))
+	viper.BindPFlag("completeWeeks", DeliveryCmd.PersistentFlags().Lookup("complete-weeks"))
+	viper.BindPFlag("sinceDate", DeliveryCmd.PersistentFlags().Lookup("since"))
+	viper.BindPFlag("untilDate", DeliveryCmd.PersistentFlags().Lookup("until"))
+	viper.BindPFlag("period", DeliveryCmd.PersistentFlags().Lookup("period"))
+	viper.BindPFlag("teamName", DeliveryCmd.PersistentFlags().Lookup("team"))
+	viper.BindPFlag("allTeams", DeliveryCmd.PersistentFlags().Lookup("all-teams"))
+	viper.BindPFlag("repoMappingFile", DeliveryCmd.PersistentFlags().Lookup("repo-mapping-file"))
+	viper.BindPFlag("repoTags", DeliveryCmd.PersistentFlags().Lookup("repo-tags"))
+	viper.BindPFlag("excludeRepoTags", DeliveryCmd.PersistentFlags().Lookup("exclude-repo-tags"))
+	viper.BindPFlag("groupBy", DeliveryCmd.PersistentFlags().Lookup("group-by"))
+	viper.BindPFlag("comparePrevious", DeliveryCmd.PersistentFlags().Lookup("compare-previous"))
+	viper.BindPFlag("delivery.deployment_source", DeliveryCmd.PersistentFlags().Lookup("deployment-source"))
+	viper.BindPFlag("delivery.incident_labels", DeliveryCmd.PersistentFlags().Lookup("incident-labels"))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package delivery
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// getChangeFailureRateCmd represents the 'repo delivery changeFailureRate' command
+var (
+	getChangeFailureRateCmd = &cobra.Command{
+		Use:   "changeFailureRate",
+		Short: "Change failure rate for the named team's repositories",
+		Long: `Calculates the 'change failure rate' (the number of failures as a percentage
+of the number of deployments) for each of the repositories managed by the
+named team in the defined time window, and for the team as a whole; a
+failure is either a merged PR that reverts an earlier change or an issue
+or PR that includes one of the incident labels (the 'incident' label by
+default)`,
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getChangeFailureRate))
+		},
+	}
+)
+
+func init() {
+	repo.DeliveryCmd.AddCommand(getChangeFailureRateCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+}
+
+/*
+ * a utility function that returns the change failure rate (as a percentage) for the
+ * input number of failures and deployments (or nil if there were no deployments)
+ */
+func getFailureRate(numFailures int, numDeployments int) interface{} {
+	if numDeployments == 0 {
+		return nil
+	}
+	return roundValue(float64(numFailures) / float64(numDeployments) * 100)
+}
+
+/*
+ * define the function that is used to calculate the change failure rate for each of
+ * the (public, unarchived) repositories managed by the named team, along with the
+ * change failure rate for the team as a whole
+ */
+func getChangeFailureRate(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	// next, retrieve the list of repositories that are managed by the team we're looking for
+	teamName, repositoryList := utils.GetTeamRepos()
+	// save date and datetime strings for use in output (below)
+	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
+	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
+	startDateTimeStr := startDateTime.Format(cmd.ISO8601_FormatStr)
+	endDateTimeStr := endDateTime.Format(cmd.ISO8601_FormatStr)
+	// then retrieve the deployments, merged PRs, and incidents for each of those repositories
+	deploymentsByRepo := getDeploymentsByRepo(client, repositoryList, startDateTime)
+	mergedPrsByRepo := getMergedPrsByRepo(client, repositoryList, startDateTime, endDateTime)
+	incidentsByRepo := getIncidentsByRepo(client, repositoryList, startDateTime, endDateTime)
+	// and count the failures in each repository
+	byRepo := map[string]interface{}{}
+	totalDeployments := 0
+	totalFailures := 0
+	totalReverts := 0
+	totalIncidents := 0
+	for orgAndRepoName, deploymentTimes := range deploymentsByRepo {
+		numDeployments := countDeploymentsInWindow(deploymentTimes, startDateTime, endDateTime)
+		// a revert PR that is also labelled as an incident should only be counted
+		// once, so keep track of the failures we've seen by URL
+		failureUrls := map[string]bool{}
+		numReverts := 0
+		for _, pullRequest := range mergedPrsByRepo[orgAndRepoName] {
+			if isRevert(pullRequest) {
+				failureUrls[pullRequest.Url] = true
+				numReverts++
+			}
+		}
+		numIncidents := len(incidentsByRepo[orgAndRepoName])
+		for _, url := range incidentsByRepo[orgAndRepoName] {
+			failureUrls[url] = true
+		}
+		numFailures := len(failureUrls)
+		byRepo[orgAndRepoName] = map[string]interface{}{"deployments": numDeployments,
+			"failures": numFailures, "reverts": numReverts, "incidents": numIncidents,
+			"changeFailureRate": getFailureRate(numFailures, numDeployments)}
+		totalDeployments += numDeployments
+		totalFailures += numFailures
+		totalReverts += numReverts
+		totalIncidents += numIncidents
+	}
+	// print a message indicating how many failures and deployments were found
+	if totalDeployments == 0 {
+		fmt.Fprintf(os.Stderr, "\nWARN: No deployments found in repositories managed by the '%s' team\n", teamName)
+	} else {
+		fmt.Fprintf(os.Stderr, "\nFound %d failures and %d deployments in repositories managed by the '%s' team between %s and %s\n",
+			totalFailures, totalDeployments, teamName, startDateStr, endDateStr)
+	}
+	// and return the results as a map
+	return map[string]interface{}{"title": "Change Failure Rate", "start": startDateTimeStr,
+		"end": endDateTimeStr, "team": teamName, "deploymentSource": getDeploymentSource(), "byRepo": byRepo,
+		"total": map[string]interface{}{"deployments": totalDeployments, "failures": totalFailures,
+			"reverts": totalReverts, "incidents": totalIncidents,
+			"changeFailureRate": getFailureRate(totalFailures, totalDeployments)}}
+}
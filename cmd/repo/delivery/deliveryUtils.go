@@ -0,0 +1,522 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// define the default values used for the configurable parts of our delivery
+// metrics (what is counted as a deployment and the labels used to mark an
+// issue or PR as an incident)
+var (
+	defaultDeploymentSource = "both"
+	defaultIncidentLabels   = []string{"incident"}
+)
+
+// GitHub can only order the tags in a repository by the date of the tagged commit (not by
+// the time the tag was created), so when looking for the tags created within our time window
+// we keep looking until we find a tag on a commit that was made this long before the start
+// of that window; tags added to commits older than this are missed
+const tagCommitLookback = 90 * 24 * time.Hour
+
+/*
+ * define a pair of structs that can be used to query GitHub for the issues and PRs (in a
+ * given organization) that match a given query; this is used to find the issues and PRs
+ * that were labelled as incidents (the first struct is used to query for the first page
+ * of results and the second is used to query for subsequent pages of results)
+ */
+type incidentSearchEdges []struct {
+	Cursor githubv4.String
+	Node   struct {
+		Issue struct {
+			Url        string
+			Repository cmd.Repository
+		} `graphql:"... on Issue"`
+		PullRequest struct {
+			Url        string
+			Repository cmd.Repository
+		} `graphql:"... on PullRequest"`
+	}
+}
+
+type incidentSearchBody struct {
+	IssueCount githubv4.Int
+	Edges      incidentSearchEdges
+	PageInfo   cmd.PageInfo
+}
+
+var firstIncidentSearchQuery struct {
+	Search struct {
+		incidentSearchBody
+	} `graphql:"search(first: $first, query: $query, type: $type)"`
+}
+
+var incidentSearchQuery struct {
+	Search struct {
+		incidentSearchBody
+	} `graphql:"search(first: $first, after: $after, query: $query, type: $type)"`
+}
+
+/*
+ * retrieve the source of the deployments we should count (from the '--deployment-source'
+ * flag or the 'delivery.deployment_source' configuration value); this should be either
+ * 'releases', 'tags', or 'both' (the default)
+ */
+func getDeploymentSource() string {
+	source := strings.ToLower(viper.GetString("delivery.deployment_source"))
+	if source == "" {
+		return defaultDeploymentSource
+	}
+	if source != "releases" && source != "tags" && source != "both" {
+		fmt.Fprintf(os.Stderr, "ERROR: unrecognized deployment source '%s'; expected 'releases', 'tags', or 'both'\n", source)
+		os.Exit(-9)
+	}
+	return source
+}
+
+/*
+ * retrieve the list of labels used to mark an issue or PR as an incident (from the
+ * '--incident-labels' flag or the 'delivery.incident_labels' configuration value)
+ */
+func getIncidentLabels() []string {
+	labelList := utils.GetConfigStringList("delivery.incident_labels")
+	if len(labelList) == 0 {
+		return defaultIncidentLabels
+	}
+	return labelList
+}
+
+/*
+ * a utility function that splits a repository name of the form 'org/repo' into the
+ * owner and name values used in our GraphQL queries
+ */
+func splitRepoName(orgAndRepoName string) (githubv4.String, githubv4.String) {
+	owner, name, found := strings.Cut(orgAndRepoName, "/")
+	if !found {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to parse repository name '%s'; expected 'org/repo'\n", orgAndRepoName)
+		os.Exit(-9)
+	}
+	return githubv4.String(owner), githubv4.String(name)
+}
+
+/*
+ * a utility function that returns the number of weeks in the input time window
+ * (used to turn a count of deployments into a deployment frequency)
+ */
+func getWeeksInWindow(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) float64 {
+	return endDateTime.Sub(startDateTime.Time).Hours() / (24 * 7)
+}
+
+/*
+ * a utility function that rounds the input value to two decimal places (for output)
+ */
+func roundValue(val float64) float64 {
+	return math.Round(val*100) / 100
+}
+
+/*
+ * a function that retrieves the times of all of the deployments made in the named repository
+ * (of the form 'org/repo') since the start of our time window (including any made after the end
+ * of our time window, since those are needed to find the release containing a given change);
+ * depending on the deployment source, a deployment is either a (published, non-draft) release,
+ * a tag, or both (in which case a tag that has an associated release is only counted once, at
+ * the time the release was published); prereleases are skipped unless the
+ * 'delivery.include_prereleases' configuration value is set. The times returned are sorted
+ * in ascending order, and the flag returned alongside them is set if the repository is
+ * archived, or is private and private repositories are being excluded (and should be skipped)
+ */
+func getRepoDeployments(client *githubv4.Client, orgAndRepoName string, startDateTime githubv4.DateTime) ([]time.Time, bool) {
+	owner, name := splitRepoName(orgAndRepoName)
+	deploymentSource := getDeploymentSource()
+	includePrereleases := viper.GetBool("delivery.include_prereleases")
+	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
+	// initialize the vars map that we'll use when making our queries
+	vars := map[string]interface{}{}
+	vars["first"] = githubv4.Int(100)
+	vars["owner"] = owner
+	vars["name"] = name
+	// and a map of tag names to deployment times (so that a tag with a release
+	// is only counted once), along with the set of tags that have a release
+	deploymentsByTag := map[string]time.Time{}
+	releaseTags := map[string]bool{}
+	skipRepo := false
+	// if we're counting releases, then gather those first
+	if deploymentSource != "tags" {
+		firstPage := true
+		var releases []repo.Release
+		var pageInfo cmd.PageInfo
+		for {
+			var err error
+			if firstPage {
+				err = client.Query(context.Background(), &repo.FirstReleasesQuery, vars)
+			} else {
+				err = client.Query(context.Background(), &repo.ReleasesQuery, vars)
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			if firstPage {
+				releases = repo.FirstReleasesQuery.Repository.Releases.Nodes
+				pageInfo = repo.FirstReleasesQuery.Repository.Releases.PageInfo
+				skipRepo = (excludePrivateRepos && repo.FirstReleasesQuery.Repository.IsPrivate) || repo.FirstReleasesQuery.Repository.IsArchived
+				firstPage = false
+			} else {
+				releases = repo.ReleasesQuery.Repository.Releases.Nodes
+				pageInfo = repo.ReleasesQuery.Repository.Releases.PageInfo
+			}
+			fmt.Fprintf(os.Stderr, ".")
+			// if the repository is archived (or is private and we're excluding private
+			// repositories), then there's no need to go any further
+			if skipRepo {
+				return nil, skipRepo
+			}
+			reachedStart := false
+			for _, release := range releases {
+				// skip draft releases (which haven't been published)
+				if release.IsDraft || release.PublishedAt.IsZero() {
+					continue
+				}
+				// the releases are sorted by creation time (newest first), but a release can
+				// be created (as a draft) well before it is published, so we only stop looking
+				// once we find a release that was both created and published before the start
+				// of our time window
+				if release.CreatedAt.Before(startDateTime.Time) && release.PublishedAt.Before(startDateTime.Time) {
+					reachedStart = true
+					break
+				}
+				releaseTags[release.TagName] = true
+				// skip releases published before the start of our time window and prereleases
+				// (unless we were asked to include them)
+				if release.PublishedAt.Before(startDateTime.Time) || (release.IsPrerelease && !includePrereleases) {
+					continue
+				}
+				deploymentsByTag[release.TagName] = release.PublishedAt.Time
+			}
+			// if we've reached the start of our time window or the end of the list of releases,
+			// break out of the loop
+			if reachedStart || !pageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = pageInfo.EndCursor
+		}
+		delete(vars, "after")
+	}
+	// then, if we're counting tags, gather those
+	if deploymentSource != "releases" {
+		firstPage := true
+		var tags []repo.TagRef
+		var pageInfo cmd.PageInfo
+		for {
+			var err error
+			if firstPage {
+				err = client.Query(context.Background(), &repo.FirstTagsQuery, vars)
+			} else {
+				err = client.Query(context.Background(), &repo.TagsQuery, vars)
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			if firstPage {
+				tags = repo.FirstTagsQuery.Repository.Refs.Nodes
+				pageInfo = repo.FirstTagsQuery.Repository.Refs.PageInfo
+				skipRepo = (excludePrivateRepos && repo.FirstTagsQuery.Repository.IsPrivate) || repo.FirstTagsQuery.Repository.IsArchived
+				firstPage = false
+			} else {
+				tags = repo.TagsQuery.Repository.Refs.Nodes
+				pageInfo = repo.TagsQuery.Repository.Refs.PageInfo
+			}
+			fmt.Fprintf(os.Stderr, ".")
+			// if the repository is archived (or is private and we're excluding private
+			// repositories), then there's no need to go any further
+			if skipRepo {
+				return nil, skipRepo
+			}
+			reachedStart := false
+			for _, tag := range tags {
+				// the tags are sorted by the commit date of the tagged commit (newest first),
+				// and a tag can be added to a commit that was made well before the tag was
+				// created, so we only stop looking once we find a tag on a commit made more
+				// than the tagCommitLookback (above) before the start of our time window
+				if tag.GetCommittedDate().Before(startDateTime.Add(-tagCommitLookback)) {
+					reachedStart = true
+					break
+				}
+				// if there is a release for this tag, then it's already been counted (or
+				// it was published outside of our time window)
+				if releaseTags[tag.Name] {
+					continue
+				}
+				// otherwise, if this tag was created within our time window, count it
+				if taggedAt := tag.GetTaggedAt(); !taggedAt.Before(startDateTime.Time) {
+					deploymentsByTag[tag.Name] = taggedAt.Time
+				}
+			}
+			// if we've reached the start of our time window or the end of the list of tags,
+			// break out of the loop
+			if reachedStart || !pageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = pageInfo.EndCursor
+		}
+	}
+	// finally, return the deployment times, sorted in ascending order
+	deploymentTimes := []time.Time{}
+	for _, deployedAt := range deploymentsByTag {
+		deploymentTimes = append(deploymentTimes, deployedAt)
+	}
+	sort.Slice(deploymentTimes, func(i, j int) bool {
+		return deploymentTimes[i].Before(deploymentTimes[j])
+	})
+	return deploymentTimes, skipRepo
+}
+
+/*
+ * a function that retrieves the deployments for each of the repositories in the input list,
+ * returning a map of repository names to deployment times (archived repositories, and private
+ * ones if they are being excluded, are left out of the map)
+ */
+func getDeploymentsByRepo(client *githubv4.Client, repositoryList []string, startDateTime githubv4.DateTime) map[string][]time.Time {
+	deploymentsByRepo := map[string][]time.Time{}
+	for _, orgAndRepoName := range repositoryList {
+		deploymentTimes, skipRepo := getRepoDeployments(client, orgAndRepoName, startDateTime)
+		if skipRepo {
+			continue
+		}
+		deploymentsByRepo[orgAndRepoName] = deploymentTimes
+	}
+	return deploymentsByRepo
+}
+
+/*
+ * a utility function that counts the number of deployments in the input (sorted)
+ * list of deployment times that were made within the input time window
+ */
+func countDeploymentsInWindow(deploymentTimes []time.Time, startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) int {
+	count := 0
+	for _, deployedAt := range deploymentTimes {
+		if !deployedAt.Before(startDateTime.Time) && deployedAt.Before(endDateTime.Time) {
+			count++
+		}
+	}
+	return count
+}
+
+/*
+ * a function that retrieves the PRs that were merged into the default branch of one of the
+ * repositories in the input list within the input time window, returning a map of repository
+ * names to merged PRs (PRs in archived repositories, and in private ones if they are being
+ * excluded, are skipped)
+ */
+func getMergedPrsByRepo(client *githubv4.Client, repositoryList []string, startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string][]repo.MergedPullRequest {
+	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
+	// initialize the vars map that we'll use when making our query for merged PRs
+	vars := map[string]interface{}{}
+	vars["first"] = githubv4.Int(100)
+	vars["type"] = githubv4.SearchTypeIssue
+	startDateTimeStr := startDateTime.Format(cmd.ISO8601_FormatStr)
+	endDateTimeStr := endDateTime.Format(cmd.ISO8601_FormatStr)
+	mergedPrsByRepo := map[string][]repo.MergedPullRequest{}
+	// loop over the input organization names
+	for _, orgName := range utils.GetOrgNameList() {
+		// define the query to run for this organization; this query looks for PRs
+		// that were merged within the defined time window
+		vars["query"] = githubv4.String(fmt.Sprintf("org:%s type:pr is:merged merged:%s..%s", orgName,
+			startDateTimeStr, endDateTimeStr))
+		// initialize the flag that we use to determine if we're trying to retrieve
+		// the first page of results for this query (or not)
+		firstPage := true
+		// and a few other variables that we'll use to query the system for results
+		var err error
+		var edges repo.MergedPrSearchEdges
+		var pageInfo cmd.PageInfo
+		// loop over the pages of results from this query until we've reached the end
+		// of the list of PRs that matched
+		for {
+			if firstPage {
+				err = client.Query(context.Background(), &repo.FirstMergedPrSearchQuery, vars)
+			} else {
+				err = client.Query(context.Background(), &repo.MergedPrSearchQuery, vars)
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			// grab out the list of edges and the page info from the results of our search
+			if firstPage {
+				edges = repo.FirstMergedPrSearchQuery.Search.Edges
+				pageInfo = repo.FirstMergedPrSearchQuery.Search.PageInfo
+				firstPage = false
+			} else {
+				edges = repo.MergedPrSearchQuery.Search.Edges
+				pageInfo = repo.MergedPrSearchQuery.Search.PageInfo
+			}
+			fmt.Fprintf(os.Stderr, ".")
+			for _, edge := range edges {
+				pullRequest := edge.Node.MergedPullRequest
+				// if the repository for this PR is not managed by the team we're interested in, skip it
+				orgAndRepoName := orgName + "/" + pullRequest.Repository.Name
+				if len(pullRequest.Repository.Name) == 0 || utils.FindIndexOf(orgAndRepoName, repositoryList) < 0 {
+					continue
+				}
+				// if the repository associated with this PR is archived (or is private and we're
+				// excluding private repositories), then skip it
+				if (excludePrivateRepos && pullRequest.Repository.IsPrivate) || pullRequest.Repository.IsArchived {
+					continue
+				}
+				// only changes merged into the default branch end up in a release
+				if pullRequest.BaseRefName != pullRequest.Repository.DefaultBranchRef.Name {
+					continue
+				}
+				mergedPrsByRepo[orgAndRepoName] = append(mergedPrsByRepo[orgAndRepoName], pullRequest)
+			}
+			// if we've reached the end of the list of PRs, break out of the loop
+			if !pageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = pageInfo.EndCursor
+		}
+		// and unset the "after" key in the vars map so that we're ready
+		// for the next query
+		delete(vars, "after")
+	}
+	return mergedPrsByRepo
+}
+
+/*
+ * a function that retrieves the URLs of the issues and PRs (in one of the repositories in the
+ * input list) that were created within the input time window and that include one of the
+ * incident labels, returning a map of repository names to URLs (archived repositories, and
+ * private ones if they are being excluded, are skipped)
+ */
+func getIncidentsByRepo(client *githubv4.Client, repositoryList []string, startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string][]string {
+	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
+	// initialize the vars map that we'll use when making our query for incidents; note
+	// that a comma-separated list of labels in a search matches any of those labels
+	quotedLabels := []string{}
+	for _, label := range getIncidentLabels() {
+		quotedLabels = append(quotedLabels, fmt.Sprintf("%q", label))
+	}
+	vars := map[string]interface{}{}
+	vars["first"] = githubv4.Int(100)
+	vars["type"] = githubv4.SearchTypeIssue
+	incidentsByRepo := map[string][]string{}
+	for _, orgName := range utils.GetOrgNameList() {
+		vars["query"] = githubv4.String(fmt.Sprintf("org:%s label:%s created:%s..%s", orgName,
+			strings.Join(quotedLabels, ","), startDateTime.Format(cmd.ISO8601_FormatStr),
+			endDateTime.Format(cmd.ISO8601_FormatStr)))
+		// initialize the flag that we use to determine if we're trying to retrieve
+		// the first page of results for this query (or not)
+		firstPage := true
+		var err error
+		var edges incidentSearchEdges
+		var pageInfo cmd.PageInfo
+		for {
+			if firstPage {
+				err = client.Query(context.Background(), &firstIncidentSearchQuery, vars)
+			} else {
+				err = client.Query(context.Background(), &incidentSearchQuery, vars)
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			// grab out the list of edges and the page info from the results of our search
+			if firstPage {
+				edges = firstIncidentSearchQuery.Search.Edges
+				pageInfo = firstIncidentSearchQuery.Search.PageInfo
+				firstPage = false
+			} else {
+				edges = incidentSearchQuery.Search.Edges
+				pageInfo = incidentSearchQuery.Search.PageInfo
+			}
+			fmt.Fprintf(os.Stderr, ".")
+			for _, edge := range edges {
+				// the search results include both issues and PRs, so grab the URL and
+				// repository from whichever one this result is
+				url, repository := edge.Node.Issue.Url, edge.Node.Issue.Repository
+				if url == "" {
+					url, repository = edge.Node.PullRequest.Url, edge.Node.PullRequest.Repository
+				}
+				orgAndRepoName := orgName + "/" + repository.Name
+				if len(repository.Name) == 0 || utils.FindIndexOf(orgAndRepoName, repositoryList) < 0 {
+					continue
+				}
+				// if the repository is archived (or is private and we're excluding private
+				// repositories), then skip it
+				if (excludePrivateRepos && repository.IsPrivate) || repository.IsArchived {
+					continue
+				}
+				incidentsByRepo[orgAndRepoName] = append(incidentsByRepo[orgAndRepoName], url)
+			}
+			// if we've reached the end of the list of incidents, break out of the loop
+			if !pageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = pageInfo.EndCursor
+		}
+		// and unset the "after" key in the vars map so that we're ready
+		// for the next query
+		delete(vars, "after")
+	}
+	return incidentsByRepo
+}
+
+/*
+ * a utility function that returns true if the input PR reverts an earlier change (using
+ * the title that GitHub and git give to revert commits and PRs by default)
+ */
+func isRevert(pullRequest repo.MergedPullRequest) bool {
+	return strings.HasPrefix(pullRequest.Title, "Revert \"") || strings.HasPrefix(pullRequest.Title, "Revert '")
+}
+
+/*
+ * a utility function that returns the lead time for the changes in the input PR (the time
+ * from the first commit on the PR to the first deployment made after the PR was merged)
+ * along with a flag indicating whether or not a lead time could be calculated (it can't
+ * if the PR has no commits or if there has been no deployment since the PR was merged)
+ */
+func getLeadTime(pullRequest repo.MergedPullRequest, deploymentTimes []time.Time) (time.Duration, bool) {
+	if len(pullRequest.Commits.Nodes) == 0 {
+		return 0, false
+	}
+	// we use the time the first commit was authored (rather than committed), since
+	// rebasing a PR changes the commit time of all of the commits it contains
+	firstCommitAt := pullRequest.Commits.Nodes[0].Commit.AuthoredDate.Time
+	// the deployment times are sorted in ascending order, so the first one that isn't
+	// before the time the PR was merged is the release that contains it
+	idx := sort.Search(len(deploymentTimes), func(i int) bool {
+		return !deploymentTimes[i].Before(pullRequest.MergedAt.Time)
+	})
+	if idx == len(deploymentTimes) {
+		return 0, false
+	}
+	return deploymentTimes[idx].Sub(firstCommitAt), true
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package delivery
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// getDeploymentFrequencyCmd represents the 'repo delivery deploymentFrequency' command
+var (
+	getDeploymentFrequencyCmd = &cobra.Command{
+		Use:   "deploymentFrequency",
+		Short: "Deployment frequency for the named team's repositories",
+		Long: `Counts the number of deployments (releases and/or tags) made in each of the
+repositories managed by the named team in the defined time window, along
+with the number of deployments per week, and rolls those numbers up for
+the team as a whole`,
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getDeploymentFrequency))
+		},
+	}
+)
+
+func init() {
+	repo.DeliveryCmd.AddCommand(getDeploymentFrequencyCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+}
+
+/*
+ * define the function that is used to calculate the deployment frequency for each of
+ * the (public, unarchived) repositories managed by the named team, along with the
+ * deployment frequency for the team as a whole
+ */
+func getDeploymentFrequency(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	// next, retrieve the list of repositories that are managed by the team we're looking for
+	teamName, repositoryList := utils.GetTeamRepos()
+	// save date and datetime strings for use in output (below)
+	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
+	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
+	startDateTimeStr := startDateTime.Format(cmd.ISO8601_FormatStr)
+	endDateTimeStr := endDateTime.Format(cmd.ISO8601_FormatStr)
+	weeksInWindow := getWeeksInWindow(startDateTime, endDateTime)
+	// then retrieve the deployments made in each of those repositories and count the
+	// ones that were made within our time window
+	byRepo := map[string]interface{}{}
+	totalDeployments := 0
+	reposWithDeployments := 0
+	for orgAndRepoName, deploymentTimes := range getDeploymentsByRepo(client, repositoryList, startDateTime) {
+		numDeployments := countDeploymentsInWindow(deploymentTimes, startDateTime, endDateTime)
+		byRepo[orgAndRepoName] = map[string]interface{}{"deployments": numDeployments,
+			"deploymentsPerWeek": roundValue(float64(numDeployments) / weeksInWindow)}
+		totalDeployments += numDeployments
+		if numDeployments > 0 {
+			reposWithDeployments++
+		}
+	}
+	// print a message indicating how many deployments were found
+	if totalDeployments == 0 {
+		fmt.Fprintf(os.Stderr, "\nWARN: No deployments found in repositories managed by the '%s' team\n", teamName)
+	} else {
+		fmt.Fprintf(os.Stderr, "\nFound %d deployments in repositories managed by the '%s' team between %s and %s\n",
+			totalDeployments, teamName, startDateStr, endDateStr)
+	}
+	// and return the results as a map
+	return map[string]interface{}{"title": "Deployment Frequency", "start": startDateTimeStr,
+		"end": endDateTimeStr, "team": teamName, "deploymentSource": getDeploymentSource(), "byRepo": byRepo,
+		"total": map[string]interface{}{"repositories": len(byRepo), "reposWithDeployments": reposWithDeployments,
+			"deployments": totalDeployments, "deploymentsPerWeek": roundValue(float64(totalDeployments) / weeksInWindow)}}
+}
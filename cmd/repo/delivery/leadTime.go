@@ -0,0 +1,102 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package delivery
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// getLeadTimeStatsCmd represents the 'repo delivery leadTime' command
+var (
+	getLeadTimeStatsCmd = &cobra.Command{
+		Use:   "leadTime",
+		Short: "Statistics for the 'lead time for changes' of merged PRs",
+		Long: `Calculates the distribution statistics for the 'lead time for changes' (the
+time from the first commit on a PR to the first deployment made after
+that PR was merged) for all of the PRs merged into the default branch of
+the repositories managed by the named team in the defined time window;
+results are reported for each repository and for the team as a whole,
+along with the number of merged PRs that have not been deployed yet`,
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getLeadTimeStats))
+		},
+	}
+)
+
+func init() {
+	repo.DeliveryCmd.AddCommand(getLeadTimeStatsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+}
+
+/*
+ * define the function that is used to calculate the statistics associated with the
+ * "lead time for changes" for the PRs merged into the (public, unarchived) repositories
+ * managed by the named team
+ */
+func getLeadTimeStats(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	// next, retrieve the list of repositories that are managed by the team we're looking for
+	teamName, repositoryList := utils.GetTeamRepos()
+	// save date and datetime strings for use in output (below)
+	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
+	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
+	startDateTimeStr := startDateTime.Format(cmd.ISO8601_FormatStr)
+	endDateTimeStr := endDateTime.Format(cmd.ISO8601_FormatStr)
+	// then retrieve the deployments made in each of those repositories (which are used
+	// to find the release containing each change) and the PRs merged in our time window
+	deploymentsByRepo := getDeploymentsByRepo(client, repositoryList, startDateTime)
+	mergedPrsByRepo := getMergedPrsByRepo(client, repositoryList, startDateTime, endDateTime)
+	// and calculate the lead times for the PRs in each repository
+	byRepo := map[string]interface{}{}
+	leadTimeList := []time.Duration{}
+	totalUndeployed := 0
+	for orgAndRepoName, deploymentTimes := range deploymentsByRepo {
+		repoLeadTimes := []time.Duration{}
+		numUndeployed := 0
+		for _, pullRequest := range mergedPrsByRepo[orgAndRepoName] {
+			leadTime, deployed := getLeadTime(pullRequest, deploymentTimes)
+			if !deployed {
+				numUndeployed++
+				continue
+			}
+			repoLeadTimes = append(repoLeadTimes, leadTime)
+		}
+		leadTimeList = append(leadTimeList, repoLeadTimes...)
+		totalUndeployed += numUndeployed
+		repoStats, numDeployed := utils.GetJsonDurationStats(repoLeadTimes)
+		byRepo[orgAndRepoName] = map[string]interface{}{"seriesLength": numDeployed,
+			"undeployed": numUndeployed, "stats": repoStats}
+	}
+	// calculate the stats for the team as a whole
+	leadTimeStats, numDeployed := utils.GetJsonDurationStats(leadTimeList)
+	// print a message indicating how many deployed PRs were found
+	if numDeployed == 0 {
+		fmt.Fprintf(os.Stderr, "\nWARN: No deployed PRs found in repositories managed by the '%s' team\n", teamName)
+	} else {
+		fmt.Fprintf(os.Stderr, "\nFound %d deployed PRs in repositories managed by the '%s' team that were merged between %s and %s\n",
+			numDeployed, teamName, startDateStr, endDateStr)
+	}
+	// and return the results as a map
+	return map[string]interface{}{"title": "Lead Time for Changes", "start": startDateTimeStr,
+		"end": endDateTimeStr, "team": teamName, "deploymentSource": getDeploymentSource(), "byRepo": byRepo,
+		"total": map[string]interface{}{"seriesLength": numDeployed, "undeployed": totalUndeployed, "stats": leadTimeStats}}
+}
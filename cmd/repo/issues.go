@@ -84,8 +84,15 @@ func init() {
 	IssuesCmd.PersistentFlags().StringVarP(&cmd.LookbackTime, "lookback-time", "l", "", "'lookback' time window (eg. 10d, 3w, 2m, 1q, 1y)")
 	IssuesCmd.PersistentFlags().StringVarP(&cmd.ReferenceDate, "ref-date", "d", "", "reference date for time window (YYYY-MM-DD)")
 	IssuesCmd.PersistentFlags().BoolVarP(&cmd.CompleteWeeks, "complete-weeks", "w", false, "only output complete weeks (starting Monday)")
-	IssuesCmd.PersistentFlags().StringVarP(&cmd.CompTeam, "team", "t", "", "name of team to restrict repository list to")
+	IssuesCmd.PersistentFlags().StringVar(&cmd.SinceDate, "since", "", "start date for time window (YYYY-MM-DD)")
+	IssuesCmd.PersistentFlags().StringVar(&cmd.UntilDate, "until", "", "end date (inclusive) for time window (YYYY-MM-DD)")
+	IssuesCmd.PersistentFlags().StringVar(&cmd.Period, "period", "", "named time window (eg. 2026-Q3, 2026-09, 2026-W37, last-quarter)")
+	IssuesCmd.PersistentFlags().StringVarP(&cmd.CompTeam, "team", "t", "", "name of team (or comma-separated list of teams) to restrict repository list to")
+	IssuesCmd.PersistentFlags().BoolVar(&cmd.AllTeams, "all-teams", false, "gather data for each of the teams defined in the configuration file")
 	IssuesCmd.PersistentFlags().StringVarP(&cmd.RepoMappingFile, "repo-mapping-file", "m", "", "name of the repository mapping file to use")
+	IssuesCmd.PersistentFlags().StringVar(&cmd.RepoTags, "repo-tags", "", "only include repositories with one of these (comma-separated) tags")
+	IssuesCmd.PersistentFlags().StringVar(&cmd.ExcludeRepoTags, "exclude-repo-tags", "", "exclude repositories with any of these (comma-separated) tags")
+	IssuesCmd.PersistentFlags().StringVar(&cmd.GroupBy, "group-by", "", "report separately for each repository group (currently only 'tag')")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
@@ -94,6 +101,13 @@ func init() {
 	viper.BindPFlag("lookbackTime", IssuesCmd.PersistentFlags().Lookup("lookback-time"))
 	viper.BindPFlag("referenceDate", IssuesCmd.PersistentFlags().Lookup("ref-date"))
 	viper.BindPFlag("completeWeeks", IssuesCmd.PersistentFlags().Lookup("complete-weeks"))
+	viper.BindPFlag("sinceDate", IssuesCmd.PersistentFlags().Lookup("since"))
+	viper.BindPFlag("untilDate", IssuesCmd.PersistentFlags().Lookup("until"))
+	viper.BindPFlag("period", IssuesCmd.PersistentFlags().Lookup("period"))
 	viper.BindPFlag("teamName", IssuesCmd.PersistentFlags().Lookup("team"))
+	viper.BindPFlag("allTeams", IssuesCmd.PersistentFlags().Lookup("all-teams"))
 	viper.BindPFlag("repoMappingFile", IssuesCmd.PersistentFlags().Lookup("repo-mapping-file"))
+	viper.BindPFlag("repoTags", IssuesCmd.PersistentFlags().Lookup("repo-tags"))
+	viper.BindPFlag("excludeRepoTags", IssuesCmd.PersistentFlags().Lookup("exclude-repo-tags"))
+	viper.BindPFlag("groupBy", IssuesCmd.PersistentFlags().Lookup("group-by"))
 }
@@ -26,7 +26,7 @@ and in the defined time window (skipping any issues that include the
 'backlog' label and only counting issues in repositories that are managed
 by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(getClosedIssueCount())
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getClosedIssueCount))
 		},
 	}
 )
@@ -41,8 +41,10 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
+	getClosedIssuesCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
 
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("comparePrevious", getClosedIssuesCmd.Flags().Lookup("compare-previous"))
 }
 
 /*
@@ -51,7 +53,7 @@ func init() {
  * include the 'backlog' label and only counts issues in repositories that are
  * managed by the named team(s)
  */
-func getClosedIssueCount() map[string]interface{} {
+func getClosedIssueCount(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
 	// first, get a new GitHub GraphQL API client
 	client := utils.GetAuthenticatedClient()
 	// initialize the vars map that we'll use when making our query for closed issues
@@ -69,8 +71,6 @@ func getClosedIssueCount() map[string]interface{} {
 	teamName, repositoryList := utils.GetTeamRepos()
 	// should we filter out private repositories?
 	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
-	// retrieve the start and end time for our query window
-	startDateTime, endDateTime := utils.GetQueryTimeWindow()
 	// save date strings for use in output (below)
 	// save date and datetime strings for use in output (below)
 	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
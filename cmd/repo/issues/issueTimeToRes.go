@@ -28,7 +28,7 @@ organizations and in the defined time window (skipping any issues that include
 the 'backlog' label and only counting issues in repositories that are managed
 by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(getTimeToResStats())
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getTimeToResStats))
 		},
 	}
 )
@@ -43,8 +43,10 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
+	getTimeToResStatsCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
 
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("comparePrevious", getTimeToResStatsCmd.Flags().Lookup("compare-previous"))
 }
 
 /*
@@ -54,7 +56,7 @@ func init() {
  * includes first response times for issues in repositories that are managed by the
  * named team(s)
  */
-func getTimeToResStats() map[string]interface{} {
+func getTimeToResStats(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
 	// first, get a new GitHub GraphQL API client
 	client := utils.GetAuthenticatedClient()
 	// initialize the vars map that we'll use when making our query for closed issues
@@ -66,8 +68,6 @@ func getTimeToResStats() map[string]interface{} {
 	teamName, repositoryList := utils.GetTeamRepos()
 	// should we filter out private repositories?
 	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
-	// retrieve the start and end time for our query window
-	startDateTime, endDateTime := utils.GetQueryTimeWindow()
 	// save date and datetime strings for use in output (below)
 	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
 	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
@@ -28,7 +28,7 @@ GitHub organization that were closed in the defined time window (skipping any is
 that include the 'backlog' label and only including issues from repositories that are
 managed by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(listClosedIssueCount())
+			utils.DumpMapAsJSON(utils.GetResultsByGroup(listClosedIssueCount))
 		},
 	}
 )
@@ -162,9 +162,9 @@ func listClosedIssueCount() []map[string]interface{} {
 						// the time it was closed)
 						issueAge := issue.ClosedAt.Time.Sub(issue.CreatedAt.Time)
 						closedIssueList = append(closedIssueList, map[string]interface{}{
-							"createdAt":       issue.CreatedAt.Time,
+							"createdAt":       utils.InTimeZone(issue.CreatedAt.Time),
 							"closed":          issue.Closed,
-							"closedAt":        issue.ClosedAt.Time,
+							"closedAt":        utils.InTimeZone(issue.ClosedAt.Time),
 							"url":             issue.Url,
 							"title":           issue.Title,
 							"creator":         issue.Author.Login,
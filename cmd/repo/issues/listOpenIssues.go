@@ -29,7 +29,7 @@ GitHub organization in the defined time window (skipping any issues that include
 the 'backlog' label and only including issues from repositories that are managed
 by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(listOpenIssueCount())
+			utils.DumpMapAsJSON(utils.GetResultsByGroup(listOpenIssueCount))
 		},
 	}
 )
@@ -87,12 +87,14 @@ func listOpenIssueCount() []map[string]interface{} {
 	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
 	// should we only count comments from immediate team members?
 	commentsFromTeamOnly := viper.GetBool("restrictToTeam")
-	teamMemberIds := []string{}
-	if !commentsFromTeamOnly {
-		// and the details for members of the corresponding team
-		_, teamMemberMap := utils.GetTeamMembers(teamName)
-		// and from that map, construct list of member logins for that team
-		teamMemberIds = utils.GetTeamMemberIds(teamMemberMap)
+	teamRoster := utils.TeamRoster{}
+	if commentsFromTeamOnly {
+		// and the details for members of the corresponding team (or teams, including any
+		// subteams that are rolled up into them, if we're gathering results for more than
+		// one team)
+		_, teamMemberMap := utils.GetTeamMembers()
+		// and from that map, construct the roster (by login) for that team
+		teamRoster = utils.GetTeamRoster(teamMemberMap)
 	}
 	// retrieve the reference time for our query window
 	startDateTime, endDateTime := utils.GetQueryTimeWindow()
@@ -201,9 +203,9 @@ func listOpenIssueCount() []map[string]interface{} {
 						}
 						// create a map to hold the data for this issue
 						issueData := map[string]interface{}{
-							"createdAt":       issue.CreatedAt.Time,
+							"createdAt":       utils.InTimeZone(issue.CreatedAt.Time),
 							"closed":          issue.Closed,
-							"closedAt":        issue.ClosedAt.Time,
+							"closedAt":        utils.InTimeZone(issue.ClosedAt.Time),
 							"url":             issue.Url,
 							"title":           issue.Title,
 							"creator":         issue.Author.Login,
@@ -216,10 +218,10 @@ func listOpenIssueCount() []map[string]interface{} {
 						// finally, if a flag was set to sort the list of issues by the first response
 						// time or staleness time, add that field to our output map
 						if sortByFirstResponse {
-							firstResponseTime := repo.GetFirstResponseTime(&issue, endDateTime, commentsFromTeamOnly, teamMemberIds)
+							firstResponseTime := repo.GetFirstResponseTime(&issue, endDateTime, commentsFromTeamOnly, teamRoster)
 							issueData["firstResponseTime"] = utils.JsonDuration{Duration: firstResponseTime}
 						} else if sortByStaleness {
-							stalenessTime := repo.GetLatestResponseTime(&issue, endDateTime, commentsFromTeamOnly, teamMemberIds)
+							stalenessTime := repo.GetLatestResponseTime(&issue, endDateTime, commentsFromTeamOnly, teamRoster)
 							issueData["staleness"] = utils.JsonDuration{Duration: stalenessTime}
 						}
 						// and add the issue to the list of open issues
@@ -28,7 +28,7 @@ unassigned in the named GitHub organization and defined time window (skipping
 any issues that include the 'backlog' label and only including PRs from the
 repositories that are managed by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(listUnassignedIssueCount())
+			utils.DumpMapAsJSON(utils.GetResultsByGroup(listUnassignedIssueCount))
 		},
 	}
 )
@@ -174,9 +174,9 @@ func listUnassignedIssueCount() []map[string]interface{} {
 							prAge = endDateTime.Sub(issue.CreatedAt.Time)
 						}
 						unassignedPrList = append(unassignedPrList, map[string]interface{}{
-							"createdAt":       issue.CreatedAt.Time,
+							"createdAt":       utils.InTimeZone(issue.CreatedAt.Time),
 							"closed":          issue.Closed,
-							"closedAt":        issue.ClosedAt.Time,
+							"closedAt":        utils.InTimeZone(issue.ClosedAt.Time),
 							"url":             issue.Url,
 							"title":           issue.Title,
 							"creator":         issue.Author.Login,
@@ -27,7 +27,7 @@ and maximum 'age' for all open issues in the named GitHub organizations in
 the defined time window (skipping issues that include the 'backlog' label
 and only counting issues in repositories that are managed by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(getAgeStats())
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getAgeStats))
 		},
 	}
 )
@@ -42,8 +42,10 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
+	getAgeStatsCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
 
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("comparePrevious", getAgeStatsCmd.Flags().Lookup("compare-previous"))
 }
 
 /*
@@ -53,7 +55,7 @@ func init() {
  * includes first response times for issues in repositories that are managed by
  * the named team(s)
  */
-func getAgeStats() map[string]interface{} {
+func getAgeStats(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
 	// first, get a new GitHub GraphQL API client
 	client := utils.GetAuthenticatedClient()
 	// initialize the vars map that we'll use when making our queries for issues
@@ -65,8 +67,6 @@ func getAgeStats() map[string]interface{} {
 	teamName, repositoryList := utils.GetTeamRepos()
 	// should we filter out private repositories?
 	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
-	// retrieve reference time for our query window
-	startDateTime, endDateTime := utils.GetQueryTimeWindow()
 	// save date and datetime strings for use in output (below)
 	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
 	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
@@ -0,0 +1,34 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package repo
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+)
+
+// mappingCmd represents the 'mapping' command
+var (
+	MappingCmd = &cobra.Command{
+		Use:   "mapping",
+		Short: "Manage the team to repository mapping",
+		Long:  "The subcommand used as the root for all commands that manage the mapping of teams to repositories",
+	}
+)
+
+func init() {
+	cmd.RepoCmd.AddCommand(MappingCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	MappingCmd.PersistentFlags().StringVarP(&cmd.RepoMappingFile, "repo-mapping-file", "m", "", "name of the repository mapping file to use")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("repoMappingFile", MappingCmd.PersistentFlags().Lookup("repo-mapping-file"))
+}
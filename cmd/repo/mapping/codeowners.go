@@ -0,0 +1,131 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package mapping
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// codeownersCmd represents the 'repo mapping codeowners' command
+var (
+	codeownersCmd = &cobra.Command{
+		Use:   "codeowners",
+		Short: "Compares the mapping file with the repository CODEOWNERS",
+		Long: `Reads the CODEOWNERS file (if any) from each of the repositories in the named
+set of GitHub organizations, maps the teams and users listed as owners in
+those files back to the teams defined in the configuration file, and reports
+the repositories in the repository mapping file whose CODEOWNERS disagree
+with that mapping (along with the repositories that don't have a CODEOWNERS
+file and the owned repositories that are missing from the mapping file).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.DumpMapAsJSON(compareCodeowners())
+		},
+	}
+)
+
+func init() {
+	repo.MappingCmd.AddCommand(codeownersCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+}
+
+/*
+ * a utility function that returns a map of the (lower-cased) URLs of the repositories
+ * in a repository mapping to the groups that own them; since a group owns all of the
+ * repositories owned by its children, each repository is owned by the group it is
+ * listed under and by all of that group's ancestors
+ */
+func getRepoOwnerGroups(groups []MappingGroup, ancestors []string, groupsByUrl map[string][]string) map[string][]string {
+	for _, group := range groups {
+		owners := append(append([]string{}, ancestors...), group.Group)
+		for _, repository := range group.Repositories {
+			url := strings.ToLower(repository.Url)
+			for _, owner := range owners {
+				if !utils.SliceContains(groupsByUrl[url], owner) {
+					groupsByUrl[url] = append(groupsByUrl[url], owner)
+				}
+			}
+		}
+		getRepoOwnerGroups(group.Children, owners, groupsByUrl)
+	}
+	return groupsByUrl
+}
+
+/*
+ * define the function that is used to compare the repository mapping file with the
+ * CODEOWNERS files in the repositories in the named org(s)
+ */
+func compareCodeowners() map[string]interface{} {
+	// first, read the existing mapping file
+	repoMappingFile := utils.GetRepoMappingFileName()
+	mapping, found := readRepoMapping(repoMappingFile)
+	if !found {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to read the repository mapping file '%s'\n", repoMappingFile)
+		os.Exit(-7)
+	}
+	groupsByUrl := getRepoOwnerGroups(mapping, []string{}, map[string][]string{})
+	// then compare the owners of each repository (from its CODEOWNERS file) with the
+	// groups that own it in the mapping file
+	disagreements := []map[string]interface{}{}
+	noCodeowners := []string{}
+	notInMapping := []map[string]interface{}{}
+	numAgreements := 0
+	for _, repoCodeowners := range utils.GetRepoCodeowners() {
+		url := strings.ToLower(repoCodeowners.Url)
+		mappedGroups, mapped := groupsByUrl[url]
+		if !repoCodeowners.HasCodeowners {
+			if mapped {
+				noCodeowners = append(noCodeowners, repoCodeowners.Url)
+			}
+			continue
+		}
+		codeownersTeams := utils.GetCodeownersTeams(repoCodeowners.Owners)
+		if !mapped {
+			// only report unmapped repositories that are owned by one of our teams
+			if len(codeownersTeams) > 0 {
+				notInMapping = append(notInMapping, map[string]interface{}{
+					"url":             repoCodeowners.Url,
+					"codeowners":      repoCodeowners.Owners,
+					"codeownersTeams": codeownersTeams,
+				})
+			}
+			continue
+		}
+		// the CODEOWNERS agree with the mapping if any of the teams they map to is one of
+		// the groups that owns the repository in the mapping file
+		agree := false
+		for _, team := range codeownersTeams {
+			agree = agree || utils.SliceContains(mappedGroups, team)
+		}
+		if agree {
+			numAgreements++
+			continue
+		}
+		disagreements = append(disagreements, map[string]interface{}{
+			"url":             repoCodeowners.Url,
+			"mappedTo":        mappedGroups,
+			"codeowners":      repoCodeowners.Owners,
+			"codeownersTeams": codeownersTeams,
+		})
+	}
+	sort.Strings(noCodeowners)
+	fmt.Fprintf(os.Stderr, "Found %d repositories whose CODEOWNERS disagree with the mapping file\n", len(disagreements))
+	// and return the results
+	return map[string]interface{}{"title": "CODEOWNERS Disagreements", "mappingFile": repoMappingFile,
+		"agreements": numAgreements, "disagreements": disagreements,
+		"noCodeowners": noCodeowners, "notInMapping": notInMapping}
+}
@@ -0,0 +1,423 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package mapping
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// generateCmd represents the 'repo mapping generate' command
+var (
+	mappingDiff  bool
+	mappingWrite bool
+	mappingForce bool
+	generateCmd  = &cobra.Command{
+		Use:   "generate",
+		Short: "Generates the team to repository mapping",
+		Long: `Generates the mapping of teams to the repositories that they manage (in the
+form used by the repository mapping file) from the repositories in the named
+set of GitHub organizations, based on the repository permissions granted to
+GitHub teams, on repository topics, and on repository name patterns (as
+defined by the 'repo_mapping_rules' in the configuration file); the result
+is either output as YAML or compared with the existing mapping file.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			generateMapping()
+		},
+	}
+)
+
+func init() {
+	repo.MappingCmd.AddCommand(generateCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	generateCmd.Flags().BoolVar(&mappingDiff, "diff", false, "output the differences from the existing mapping file instead")
+	generateCmd.Flags().BoolVar(&mappingWrite, "write", false, "overwrite the existing mapping file (and output the differences)")
+	generateCmd.Flags().BoolVar(&mappingForce, "force", false, "overwrite the existing mapping file even if repositories or groups would be removed from it")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("mappingDiff", generateCmd.Flags().Lookup("diff"))
+	viper.BindPFlag("mappingWrite", generateCmd.Flags().Lookup("write"))
+	viper.BindPFlag("mappingForce", generateCmd.Flags().Lookup("force"))
+}
+
+/*
+ * define the struct used to read the rules for generating the mapping from the
+ * configuration file; a repository is included in a group if the GitHub team for
+ * that group has (at least) write access to it, if it has any of the named topics,
+ * or if its name matches any of the named patterns (which are glob-style patterns
+ * unless the regexp flag is set), and repositories included in a child group are
+ * not repeated in the parent group
+ */
+type mappingRule struct {
+	Group      string        `mapstructure:"group"`
+	GitHubTeam string        `mapstructure:"github_team"`
+	Topics     []string      `mapstructure:"topics"`
+	Patterns   []string      `mapstructure:"patterns"`
+	Regexp     bool          `mapstructure:"regexp"`
+	Tags       []string      `mapstructure:"tags"`
+	Children   []mappingRule `mapstructure:"children"`
+}
+
+// the repository permissions that we count as a team managing a repository
+var managingPermissions = []string{"WRITE", "MAINTAIN", "ADMIN"}
+
+/*
+ * define the structs that we'll use to retrieve the repositories in an organization
+ * (along with their topics) and the repositories that a team has access to; as with
+ * the other queries, the first struct in each pair is used to query for the first page
+ * of results and the second is used to query for subsequent pages of results
+ */
+type orgRepository struct {
+	Name             string
+	Url              string
+	IsArchived       bool
+	IsPrivate        bool
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string
+			}
+		}
+	} `graphql:"repositoryTopics(first: 20)"`
+}
+
+type orgRepositoriesBody struct {
+	Nodes    []orgRepository
+	PageInfo cmd.PageInfo
+}
+
+var firstOrgRepositoriesQuery struct {
+	Organization struct {
+		Repositories struct {
+			orgRepositoriesBody
+		} `graphql:"repositories(first: $first)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+var orgRepositoriesQuery struct {
+	Organization struct {
+		Repositories struct {
+			orgRepositoriesBody
+		} `graphql:"repositories(first: $first, after: $after)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+type teamRepositoriesBody struct {
+	Edges []struct {
+		Permission string
+		Node       struct {
+			Url string
+		}
+	}
+	PageInfo cmd.PageInfo
+}
+
+var firstTeamRepositoriesQuery struct {
+	Organization struct {
+		Team struct {
+			Slug         string
+			Repositories struct {
+				teamRepositoriesBody
+			} `graphql:"repositories(first: $first)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+var teamRepositoriesQuery struct {
+	Organization struct {
+		Team struct {
+			Slug         string
+			Repositories struct {
+				teamRepositoriesBody
+			} `graphql:"repositories(first: $first, after: $after)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+/*
+ * a utility function that returns the rules used to generate the mapping; if no rules
+ * are defined in the configuration file, then a group is generated for each of the teams
+ * in the configuration file, containing the repositories managed by the GitHub team with
+ * the same slug (or the slug defined for that team in the 'team_slugs' map)
+ */
+func getMappingRules() []mappingRule {
+	rules := []mappingRule{}
+	if err := viper.UnmarshalKey("repo_mapping_rules", &rules); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to parse the 'repo_mapping_rules' in the configuration file; %s\n", err)
+		os.Exit(-6)
+	}
+	if len(rules) > 0 {
+		return rules
+	}
+	teamSlugs := viper.GetStringMapString("team_slugs")
+	teamNames := []string{}
+	if teamsMap, ok := viper.Get("teams").(map[string]interface{}); ok {
+		for teamName := range teamsMap {
+			teamNames = append(teamNames, teamName)
+		}
+	}
+	sort.Strings(teamNames)
+	for _, teamName := range teamNames {
+		gitHubTeam := teamName
+		if slug, ok := teamSlugs[teamName]; ok {
+			gitHubTeam = slug
+		}
+		rules = append(rules, mappingRule{Group: teamName, GitHubTeam: gitHubTeam})
+	}
+	return rules
+}
+
+/*
+ * a utility function that returns the (non-archived) repositories in the named
+ * organizations
+ */
+func getOrgRepositories(client *githubv4.Client) []orgRepository {
+	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
+	repositories := []orgRepository{}
+	for _, orgName := range utils.GetOrgNameList() {
+		vars := map[string]interface{}{
+			"orgname": githubv4.String(orgName),
+			"first":   githubv4.Int(100),
+		}
+		firstPage := true
+		for {
+			var err error
+			var orgRepositories orgRepositoriesBody
+			if firstPage {
+				err = client.Query(context.Background(), &firstOrgRepositoriesQuery, vars)
+				orgRepositories = firstOrgRepositoriesQuery.Organization.Repositories.orgRepositoriesBody
+				firstPage = false
+			} else {
+				err = client.Query(context.Background(), &orgRepositoriesQuery, vars)
+				orgRepositories = orgRepositoriesQuery.Organization.Repositories.orgRepositoriesBody
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, ".")
+			for _, repository := range orgRepositories.Nodes {
+				if repository.IsArchived || (excludePrivateRepos && repository.IsPrivate) {
+					continue
+				}
+				repositories = append(repositories, repository)
+			}
+			// if we've reached the end of the list of repositories, break out of the loop
+			if !orgRepositories.PageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = orgRepositories.PageInfo.EndCursor
+		}
+	}
+	sort.Slice(repositories, func(i, j int) bool {
+		return strings.ToLower(repositories[i].Url) < strings.ToLower(repositories[j].Url)
+	})
+	return repositories
+}
+
+/*
+ * a utility function that returns the (lower-cased) URLs of the repositories that the
+ * named GitHub team (either 'org/slug' or 'slug', in which case the team is looked for
+ * in each of the named organizations) has at least write access to
+ */
+func getTeamRepoUrls(client *githubv4.Client, gitHubTeam string) map[string]bool {
+	orgNameList := utils.GetOrgNameList()
+	teamSlug := gitHubTeam
+	if orgName, slug, found := strings.Cut(gitHubTeam, "/"); found {
+		orgNameList = []string{orgName}
+		teamSlug = slug
+	}
+	repoUrls := map[string]bool{}
+	teamFound := false
+	for _, orgName := range orgNameList {
+		vars := map[string]interface{}{
+			"orgname": githubv4.String(orgName),
+			"slug":    githubv4.String(teamSlug),
+			"first":   githubv4.Int(100),
+		}
+		firstPage := true
+		for {
+			var err error
+			var slug string
+			var repositories teamRepositoriesBody
+			if firstPage {
+				err = client.Query(context.Background(), &firstTeamRepositoriesQuery, vars)
+				slug = firstTeamRepositoriesQuery.Organization.Team.Slug
+				repositories = firstTeamRepositoriesQuery.Organization.Team.Repositories.teamRepositoriesBody
+				firstPage = false
+			} else {
+				err = client.Query(context.Background(), &teamRepositoriesQuery, vars)
+				slug = teamRepositoriesQuery.Organization.Team.Slug
+				repositories = teamRepositoriesQuery.Organization.Team.Repositories.teamRepositoriesBody
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			// if the team doesn't exist in this organization, move on to the next one
+			if slug == "" {
+				break
+			}
+			teamFound = true
+			for _, edge := range repositories.Edges {
+				if utils.SliceContains(managingPermissions, edge.Permission) {
+					repoUrls[strings.ToLower(edge.Node.Url)] = true
+				}
+			}
+			// if we've reached the end of the list of repositories, break out of the loop
+			if !repositories.PageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = repositories.PageInfo.EndCursor
+		}
+	}
+	if !teamFound {
+		fmt.Fprintf(os.Stderr, "WARNING: unable to find the GitHub team '%s'; skipping\n", gitHubTeam)
+	}
+	return repoUrls
+}
+
+/*
+ * a function that generates the group (and its children) defined by the input rule,
+ * returning that group along with the (lower-cased) URLs of all of the repositories
+ * included in it (or in any of its children)
+ */
+func generateGroup(client *githubv4.Client, rule mappingRule, repositories []orgRepository) (MappingGroup, map[string]bool) {
+	group := MappingGroup{Group: rule.Group, Repositories: []MappingRepo{}}
+	claimedUrls := map[string]bool{}
+	// first, generate the children of this group (since repositories included in a
+	// child group aren't repeated in this group)
+	for _, childRule := range rule.Children {
+		childGroup, childUrls := generateGroup(client, childRule, repositories)
+		group.Children = append(group.Children, childGroup)
+		for url := range childUrls {
+			claimedUrls[url] = true
+		}
+	}
+	// then gather the criteria for this group
+	teamRepoUrls := map[string]bool{}
+	if rule.GitHubTeam != "" {
+		teamRepoUrls = getTeamRepoUrls(client, rule.GitHubTeam)
+	}
+	matchers := []func(string) bool{}
+	for _, pattern := range rule.Patterns {
+		matchers = append(matchers, repo.GetRepoNameMatcher(pattern, !rule.Regexp))
+	}
+	// and add the repositories that match any of those criteria
+	for _, repository := range repositories {
+		url := strings.ToLower(repository.Url)
+		topics := []string{}
+		for _, node := range repository.RepositoryTopics.Nodes {
+			topics = append(topics, node.Topic.Name)
+		}
+		matched := teamRepoUrls[url]
+		for _, topic := range rule.Topics {
+			matched = matched || utils.SliceContains(topics, topic)
+		}
+		for _, matcher := range matchers {
+			matched = matched || matcher(repository.Name)
+		}
+		if !matched || claimedUrls[url] {
+			continue
+		}
+		// the tags for each repository are the tags defined for the group in the rule
+		// along with the topics for that repository
+		tags := append([]string{}, rule.Tags...)
+		for _, topic := range topics {
+			if !utils.SliceContains(tags, topic) {
+				tags = append(tags, topic)
+			}
+		}
+		sort.Strings(tags)
+		group.Repositories = append(group.Repositories, MappingRepo{Url: repository.Url, Tags: tags})
+		claimedUrls[url] = true
+	}
+	return group, claimedUrls
+}
+
+/*
+ * define the function that is used to generate the mapping of teams to repositories
+ * and to output it (or the differences between it and the existing mapping file)
+ */
+func generateMapping() {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	rules := getMappingRules()
+	if len(rules) == 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: no 'repo_mapping_rules' or 'teams' found in the configuration file\n")
+		os.Exit(-5)
+	}
+	// generate the groups defined by the rules
+	repositories := getOrgRepositories(client)
+	mapping := []MappingGroup{}
+	for _, rule := range rules {
+		group, _ := generateGroup(client, rule, repositories)
+		mapping = append(mapping, group)
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+	yamlBytes, err := yaml.Marshal(mapping)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to marshal the repository mapping to YAML: %v\n", err)
+		os.Exit(-7)
+	}
+	// then compare the result with the existing mapping file (if there is one)
+	repoMappingFile := utils.GetRepoMappingFileName()
+	existingMapping, found := readRepoMapping(repoMappingFile)
+	diff := diffRepoMappings(existingMapping, mapping)
+	diff["title"] = "Repository Mapping Differences"
+	diff["mappingFile"] = repoMappingFile
+	diff["mappingFileFound"] = found
+	// and output the results
+	if viper.GetBool("mappingWrite") {
+		if repoMappingFile == "" {
+			fmt.Fprintf(os.Stderr, "ERROR: unable to find the required 'repoMapping' filename\n")
+			os.Exit(-7)
+		}
+		// output the differences before touching the existing file, and refuse to
+		// overwrite it if anything would be removed from it (unless forced to)
+		utils.DumpMapAsJSON(diff)
+		if hasRemovedRepos(diff) && !viper.GetBool("mappingForce") {
+			fmt.Fprintf(os.Stderr, "ERROR: the generated mapping would remove repositories or groups from '%s'; use '--force' to overwrite it anyway\n", repoMappingFile)
+			os.Exit(-8)
+		}
+		if err := ioutil.WriteFile(repoMappingFile, yamlBytes, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: while writing repository mapping file '%s'; %s\n", repoMappingFile, err)
+			os.Exit(-6)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote repository mapping file: %s\n", repoMappingFile)
+	} else if viper.GetBool("mappingDiff") {
+		utils.DumpMapAsJSON(diff)
+	} else {
+		if found {
+			fmt.Fprintf(os.Stderr, "Generated mapping differs from '%s' in %d group(s) (use '--diff' for details)\n",
+				repoMappingFile, len(diff["byGroup"].(map[string]interface{})))
+		}
+		utils.DumpText(string(yamlBytes))
+	}
+}
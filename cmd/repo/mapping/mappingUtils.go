@@ -0,0 +1,142 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package mapping
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+/*
+ * define the types used to read and write the repository mapping file; the
+ * structure of these types matches the structure described in the comment on
+ * the getTeamRepoMappingList function (in the utils package)
+ */
+type MappingRepo struct {
+	Url  string   `yaml:"url"`
+	Tags []string `yaml:"tags"`
+}
+
+type MappingGroup struct {
+	Group        string         `yaml:"group"`
+	Repositories []MappingRepo  `yaml:"repositories"`
+	Children     []MappingGroup `yaml:"children,omitempty"`
+}
+
+/*
+ * a utility function that reads the named repository mapping file; if the file doesn't
+ * exist, then an empty mapping is returned (along with a false value)
+ */
+func readRepoMapping(fileName string) ([]MappingGroup, bool) {
+	mapping := []MappingGroup{}
+	if fileName == "" {
+		return mapping, false
+	}
+	yfile, err := ioutil.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return mapping, false
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: while reading input YAML file '%s'; %s\n", fileName, err)
+		os.Exit(-6)
+	}
+	if err := yaml.Unmarshal(yfile, &mapping); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: while unmarshaling data from input YAML file '%s'; %s\n", fileName, err)
+		os.Exit(-6)
+	}
+	return mapping, true
+}
+
+/*
+ * a utility function that flattens a repository mapping into a map of group names
+ * to the (lower-cased) URLs of the repositories owned directly by each group
+ */
+func flattenRepoMapping(groups []MappingGroup, reposByGroup map[string][]string) map[string][]string {
+	for _, group := range groups {
+		if _, ok := reposByGroup[group.Group]; !ok {
+			reposByGroup[group.Group] = []string{}
+		}
+		for _, repo := range group.Repositories {
+			reposByGroup[group.Group] = append(reposByGroup[group.Group], strings.ToLower(repo.Url))
+		}
+		flattenRepoMapping(group.Children, reposByGroup)
+	}
+	return reposByGroup
+}
+
+/*
+ * a function that compares two repository mappings, returning (for each group) the
+ * repositories that were added or removed, along with the groups that were added or
+ * removed entirely
+ */
+func diffRepoMappings(existing []MappingGroup, generated []MappingGroup) map[string]interface{} {
+	existingRepos := flattenRepoMapping(existing, map[string][]string{})
+	generatedRepos := flattenRepoMapping(generated, map[string][]string{})
+	byGroup := map[string]interface{}{}
+	addedGroups := []string{}
+	removedGroups := []string{}
+	for groupName, repos := range generatedRepos {
+		oldRepos, ok := existingRepos[groupName]
+		if !ok {
+			addedGroups = append(addedGroups, groupName)
+		}
+		added := getMissingUrls(repos, oldRepos)
+		removed := getMissingUrls(oldRepos, repos)
+		if len(added) > 0 || len(removed) > 0 {
+			byGroup[groupName] = map[string]interface{}{"added": added, "removed": removed}
+		}
+	}
+	for groupName, repos := range existingRepos {
+		if _, ok := generatedRepos[groupName]; !ok {
+			removedGroups = append(removedGroups, groupName)
+			byGroup[groupName] = map[string]interface{}{"added": []string{}, "removed": repos}
+		}
+	}
+	sort.Strings(addedGroups)
+	sort.Strings(removedGroups)
+	return map[string]interface{}{"byGroup": byGroup, "addedGroups": addedGroups,
+		"removedGroups": removedGroups, "inSync": len(byGroup) == 0}
+}
+
+/*
+ * a utility function that returns true if the differences returned by the
+ * diffRepoMappings function (above) include any groups or repositories that
+ * would be removed from the existing repository mapping
+ */
+func hasRemovedRepos(diff map[string]interface{}) bool {
+	if len(diff["removedGroups"].([]string)) > 0 {
+		return true
+	}
+	for _, groupDiff := range diff["byGroup"].(map[string]interface{}) {
+		if len(groupDiff.(map[string]interface{})["removed"].([]string)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * a utility function that returns the URLs in the first list that aren't in the second
+ */
+func getMissingUrls(urls []string, otherUrls []string) []string {
+	missing := []string{}
+	for _, url := range urls {
+		found := false
+		for _, otherUrl := range otherUrls {
+			if url == otherUrl {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, url)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -7,9 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"regexp"
 
-	"github.com/gobwas/glob"
 	"github.com/shurcooL/githubv4"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -108,34 +106,11 @@ func repoList() map[string]interface{} {
 	for _, orgName := range utils.GetOrgNameList() {
 		// construct our query string and add it ot the vars map
 		vars["query"] = githubv4.String(fmt.Sprintf("org:%s", orgName))
-		// iniitialize a few variables that we'll use for pattern matching
-		// (depending on the flags set on the command-line)
-		var searchRE *regexp.Regexp
+		// get the function we'll use to match repository names against the search
+		// pattern (if one was specified) based on the type of pattern matching that
+		// the user asked for (glob-style or regular expression)
+		matchesSearchPattern := GetRepoNameMatcher(searchPattern, viper.GetBool("globStylePattern"))
 		var err error
-		var globPattern glob.Glob
-		searchPatternType := ""
-		// if the user specified a search pattern
-		if searchPattern != "" {
-			// and if they also specified that they wanted to use a glob-style pattern
-			// to match repository names, then compile the pattern as a glob-style
-			// pattern
-			if viper.GetBool("globStylePattern") {
-				globPattern, err = glob.Compile(searchPattern)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
-					os.Exit(1)
-				}
-				searchPatternType = "glob"
-			} else {
-				// otherwise compile the searchPattern as a regular expression
-				searchRE, err = regexp.Compile(searchPattern)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
-					os.Exit(1)
-				}
-				searchPatternType = "regexp"
-			}
-		}
 		// loop over the pages of results from this query until we've reached the end
 		// of the list of PRs that matched
 		for {
@@ -182,13 +157,9 @@ func repoList() map[string]interface{} {
 				if excludePrivateRepos && edge.Node.Repository.IsPrivate {
 					continue
 				}
-				// check to see if the repository name matches the search pattern (if one was specified)
-				// based on the type of pattern matching we're doing (if no search pattern was specified,
-				// then neither of these will be tried and the repository will be included in the list)
-				if searchPatternType == "regexp" && !searchRE.MatchString(edge.Node.Repository.Name) {
-					// if not, then skip this repository
-					continue
-				} else if searchPatternType == "glob" && !globPattern.Match(edge.Node.Repository.Name) {
+				// check to see if the repository name matches the search pattern (if one was specified;
+				// if no search pattern was specified, then every repository will be included in the list)
+				if !matchesSearchPattern(edge.Node.Repository.Name) {
 					// if not, then skip this repository
 					continue
 				}
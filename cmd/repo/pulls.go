@@ -23,8 +23,22 @@ var (
  * Define a few types that we can use to define (ane extract data from) the body of the GraphQL
  * query that will be used to retrieve the list of open PRs in the named GitHub organization(s)
  */
+type PullRequestSize struct {
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	Commits      struct {
+		TotalCount int
+	}
+}
 type PullRequest struct {
 	cmd.IssueOrPrBase
+	PullRequestSize
+	Merged   bool
+	MergedAt githubv4.DateTime
+	MergedBy struct {
+		Login string
+	}
 }
 type PrSearchEdges []struct {
 	Cursor githubv4.String
@@ -56,6 +70,108 @@ var PrSearchQuery struct {
 	} `graphql:"search(first: $first, after: $after, query: $query, type: $type)"`
 }
 
+/*
+ * Define the types used to retrieve the closed PRs in the named GitHub organization(s) along
+ * with the last 'closed' event in each PR's timeline (which is used to determine who closed
+ * the PRs that were closed without being merged)
+ */
+type ClosedPullRequest struct {
+	PullRequest
+	ClosedEvents struct {
+		Nodes []struct {
+			ClosedEvent struct {
+				Actor struct {
+					Login string
+				}
+			} `graphql:"... on ClosedEvent"`
+		}
+	} `graphql:"closedEvents: timelineItems(last: 1, itemTypes: [CLOSED_EVENT])"`
+}
+type ClosedPrSearchEdges []struct {
+	Cursor githubv4.String
+	Node   struct {
+		ClosedPullRequest `graphql:"... on PullRequest"`
+	}
+}
+type closedPrSearchBody struct {
+	IssueCount githubv4.Int
+	Edges      ClosedPrSearchEdges
+	PageInfo   cmd.PageInfo
+}
+
+var FirstClosedPrSearchQuery struct {
+	Search struct {
+		closedPrSearchBody
+	} `graphql:"search(first: $first, query: $query, type: $type)"`
+}
+
+var ClosedPrSearchQuery struct {
+	Search struct {
+		closedPrSearchBody
+	} `graphql:"search(first: $first, after: $after, query: $query, type: $type)"`
+}
+
+/*
+ * Define the types used to retrieve the (merged) PRs in the named GitHub organization(s) along
+ * with the timeline events that mark the phases of each PR's lifecycle (the times it was
+ * converted to a draft or marked as ready for review, the reviews it received, and the time
+ * it was merged)
+ */
+type PullRequestTimelineItems struct {
+	Nodes []struct {
+		Typename            string `graphql:"__typename"`
+		ReadyForReviewEvent struct {
+			CreatedAt githubv4.DateTime
+		} `graphql:"... on ReadyForReviewEvent"`
+		ConvertToDraftEvent struct {
+			CreatedAt githubv4.DateTime
+		} `graphql:"... on ConvertToDraftEvent"`
+		PullRequestReview struct {
+			SubmittedAt githubv4.DateTime
+			State       string
+			Author      struct {
+				Login string
+			}
+		} `graphql:"... on PullRequestReview"`
+		MergedEvent struct {
+			CreatedAt githubv4.DateTime
+		} `graphql:"... on MergedEvent"`
+	}
+}
+type CycleTimePullRequest struct {
+	PullRequestSize
+	CreatedAt     githubv4.DateTime
+	MergedAt      githubv4.DateTime
+	Title         string
+	Url           string
+	Author        cmd.Author
+	Repository    cmd.Repository
+	TimelineItems PullRequestTimelineItems `graphql:"timelineItems(first: 100, itemTypes: [READY_FOR_REVIEW_EVENT, CONVERT_TO_DRAFT_EVENT, PULL_REQUEST_REVIEW, MERGED_EVENT])"`
+}
+type CycleTimePrSearchEdges []struct {
+	Cursor githubv4.String
+	Node   struct {
+		CycleTimePullRequest `graphql:"... on PullRequest"`
+	}
+}
+type cycleTimePrSearchBody struct {
+	IssueCount githubv4.Int
+	Edges      CycleTimePrSearchEdges
+	PageInfo   cmd.PageInfo
+}
+
+var FirstCycleTimePrSearchQuery struct {
+	Search struct {
+		cycleTimePrSearchBody
+	} `graphql:"search(first: $first, query: $query, type: $type)"`
+}
+
+var CycleTimePrSearchQuery struct {
+	Search struct {
+		cycleTimePrSearchBody
+	} `graphql:"search(first: $first, after: $after, query: $query, type: $type)"`
+}
+
 /*
  * define a few functions to get the values we'll need from the underling PullRequest
  */
@@ -71,6 +187,37 @@ func (p *PullRequest) GetClosedAt() githubv4.DateTime {
 func (p *PullRequest) GetComments() cmd.Comments {
 	return p.Comments
 }
+func (p *PullRequestSize) GetLinesChanged() int {
+	return p.Additions + p.Deletions
+}
+func (p *PullRequest) IsMerged() bool {
+	return p.Merged
+}
+
+/*
+ * return the login of the user who closed this PR (the user who merged it for merged PRs,
+ * or the actor in the last 'closed' event in the PR's timeline for PRs that were closed
+ * without being merged)
+ */
+func (p *ClosedPullRequest) GetClosedBy() string {
+	if p.Merged {
+		return p.MergedBy.Login
+	}
+	if len(p.ClosedEvents.Nodes) > 0 {
+		return p.ClosedEvents.Nodes[0].ClosedEvent.Actor.Login
+	}
+	return ""
+}
+
+/*
+ * return true if the creator of this PR is a member of the organization (or an owner of
+ * or collaborator on the repository); PRs from anyone else are external contributions
+ */
+func (p *PullRequest) CreatorIsMember() bool {
+	return p.AuthorAssociation == "OWNER" ||
+		p.AuthorAssociation == "MEMBER" ||
+		p.AuthorAssociation == "COLLABORATOR"
+}
 
 func init() {
 	cmd.RepoCmd.AddCommand(PullsCmd)
@@ -81,8 +228,15 @@ func init() {
 	PullsCmd.PersistentFlags().StringVarP(&cmd.LookbackTime, "lookback-time", "l", "", "'lookback' time window (eg. 10d, 3w, 2m, 1q, 1y)")
 	PullsCmd.PersistentFlags().StringVarP(&cmd.ReferenceDate, "ref-date", "d", "", "reference date for time window (YYYY-MM-DD)")
 	PullsCmd.PersistentFlags().BoolVarP(&cmd.CompleteWeeks, "complete-weeks", "w", false, "only output complete weeks (starting Monday)")
-	PullsCmd.PersistentFlags().StringVarP(&cmd.CompTeam, "team", "t", "", "name of team to restrict repository list to")
+	PullsCmd.PersistentFlags().StringVar(&cmd.SinceDate, "since", "", "start date for time window (YYYY-MM-DD)")
+	PullsCmd.PersistentFlags().StringVar(&cmd.UntilDate, "until", "", "end date (inclusive) for time window (YYYY-MM-DD)")
+	PullsCmd.PersistentFlags().StringVar(&cmd.Period, "period", "", "named time window (eg. 2026-Q3, 2026-09, 2026-W37, last-quarter)")
+	PullsCmd.PersistentFlags().StringVarP(&cmd.CompTeam, "team", "t", "", "name of team (or comma-separated list of teams) to restrict repository list to")
+	PullsCmd.PersistentFlags().BoolVar(&cmd.AllTeams, "all-teams", false, "gather data for each of the teams defined in the configuration file")
 	PullsCmd.PersistentFlags().StringVarP(&cmd.RepoMappingFile, "repo-mapping-file", "m", "", "name of the repository mapping file to use")
+	PullsCmd.PersistentFlags().StringVar(&cmd.RepoTags, "repo-tags", "", "only include repositories with one of these (comma-separated) tags")
+	PullsCmd.PersistentFlags().StringVar(&cmd.ExcludeRepoTags, "exclude-repo-tags", "", "exclude repositories with any of these (comma-separated) tags")
+	PullsCmd.PersistentFlags().StringVar(&cmd.GroupBy, "group-by", "", "report separately for each repository group (currently only 'tag')")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
@@ -91,6 +245,13 @@ func init() {
 	viper.BindPFlag("lookbackTime", PullsCmd.PersistentFlags().Lookup("lookback-time"))
 	viper.BindPFlag("referenceDate", PullsCmd.PersistentFlags().Lookup("ref-date"))
 	viper.BindPFlag("completeWeeks", PullsCmd.PersistentFlags().Lookup("complete-weeks"))
+	viper.BindPFlag("sinceDate", PullsCmd.PersistentFlags().Lookup("since"))
+	viper.BindPFlag("untilDate", PullsCmd.PersistentFlags().Lookup("until"))
+	viper.BindPFlag("period", PullsCmd.PersistentFlags().Lookup("period"))
 	viper.BindPFlag("teamName", PullsCmd.PersistentFlags().Lookup("team"))
+	viper.BindPFlag("allTeams", PullsCmd.PersistentFlags().Lookup("all-teams"))
 	viper.BindPFlag("repoMappingFile", PullsCmd.PersistentFlags().Lookup("repo-mapping-file"))
+	viper.BindPFlag("repoTags", PullsCmd.PersistentFlags().Lookup("repo-tags"))
+	viper.BindPFlag("excludeRepoTags", PullsCmd.PersistentFlags().Lookup("exclude-repo-tags"))
+	viper.BindPFlag("groupBy", PullsCmd.PersistentFlags().Lookup("group-by"))
 }
@@ -6,6 +6,7 @@ package pulls
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 
 	"github.com/shurcooL/githubv4"
@@ -24,9 +25,11 @@ var (
 		Long: `Determines the number of closed PRs in the named named GitHub organizations
 and in the defined time window (skipping any PRs that include the
 'backlog' label and only counting PRs in repositories that are managed
-by the named team)`,
+by the named team), split by outcome (merged or closed without being
+merged), along with the merge rate for all PRs and for PRs from external
+contributors`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(getClosedPrCount())
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getClosedPrCount))
 		},
 	}
 )
@@ -41,8 +44,10 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
+	getClosedPrsCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
 
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("comparePrevious", getClosedPrsCmd.Flags().Lookup("compare-previous"))
 }
 
 /*
@@ -51,7 +56,7 @@ func init() {
  * note that this function skips closed PRs that include the 'backlog' label
  * and only counts PRs in repositories that are managed by the named team(s)
  */
-func getClosedPrCount() map[string]interface{} {
+func getClosedPrCount(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
 	// first, get a new GitHub GraphQL API client
 	client := utils.GetAuthenticatedClient()
 	// initialize the vars map that we'll use when making our query for closed PRs
@@ -65,12 +70,18 @@ func getClosedPrCount() map[string]interface{} {
 	// and initialize a map that will be used to store counts for each of the named organizations
 	// and a total count
 	closedPrCountMap := map[string]interface{}{}
+	// along with a similar set of counters and maps for the closed PRs that were merged, and
+	// a set of counters for the closed PRs from external contributors (creators who are not
+	// members of the organization)
+	mergedPrCount := 0
+	mergedPrCountMap := map[string]interface{}{}
+	mergeRateMap := map[string]interface{}{}
+	externalPrCount := 0
+	externalMergedPrCount := 0
 	// next, retrieve the list of repositories that are managed by the team we're looking for
 	teamName, repositoryList := utils.GetTeamRepos()
 	// should we filter out private repositories?
 	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
-	// retrieve the start and end time for our query window
-	startDateTime, endDateTime := utils.GetQueryTimeWindow()
 	// save date and datetime strings for use in output (below)
 	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
 	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
@@ -78,8 +89,9 @@ func getClosedPrCount() map[string]interface{} {
 	endDateTimeStr := endDateTime.Format(cmd.ISO8601_FormatStr)
 	// loop over the input organization names
 	for _, orgName := range utils.GetOrgNameList() {
-		// initialize a counter for the number of closed PRs in the current organization
+		// initialize a counter for the number of closed (and merged) PRs in the current organization
 		orgClosedPrCount := 0
+		orgMergedPrCount := 0
 		// define the query to run for each organization; the query searches for closed
 		// PRs that were closed after the start of our time window and before the end
 		// of our time window
@@ -152,6 +164,17 @@ func getClosedPrCount() map[string]interface{} {
 						}
 						orgClosedPrCount++
 						closedPrCount++
+						// then count this PR by outcome (and by whether or not it's an external contribution)
+						if pullRequest.Merged {
+							orgMergedPrCount++
+							mergedPrCount++
+						}
+						if !pullRequest.CreatorIsMember() {
+							externalPrCount++
+							if pullRequest.Merged {
+								externalMergedPrCount++
+							}
+						}
 					}
 				}
 				// if we've reached the end of the list of contributions, break out of the loop
@@ -167,13 +190,39 @@ func getClosedPrCount() map[string]interface{} {
 
 		// add the closed PR count for the current organization to the closedPrCountMap
 		closedPrCountMap[orgName] = orgClosedPrCount
+		mergedPrCountMap[orgName] = orgMergedPrCount
+		mergeRateMap[orgName] = getMergeRate(orgMergedPrCount, orgClosedPrCount)
 	}
 	// add the total closed PR count to the closedPrCountMap
 	closedPrCountMap["total"] = closedPrCount
+	mergedPrCountMap["total"] = mergedPrCount
+	mergeRateMap["total"] = getMergeRate(mergedPrCount, closedPrCount)
+	// and use the merged PR counts to determine the number of PRs closed without being merged
+	closedWithoutMergeCountMap := map[string]interface{}{}
+	for key, val := range closedPrCountMap {
+		closedWithoutMergeCountMap[key] = val.(int) - mergedPrCountMap[key].(int)
+	}
 	// print a message indicating the total number of closed PRs found
-	fmt.Fprintf(os.Stderr, "\nFound %d closed PRs in repositories managed by the '%s' team between %s and %s\n", closedPrCount,
-		teamName, startDateStr, endDateStr)
+	fmt.Fprintf(os.Stderr, "\nFound %d closed PRs (%d merged) in repositories managed by the '%s' team between %s and %s\n", closedPrCount,
+		mergedPrCount, teamName, startDateStr, endDateStr)
 	// and return the closed PR counts as a map
 	return map[string]interface{}{"title": "Closed PR Counts", "start": startDateTimeStr,
-		"end": endDateTimeStr, "counts": closedPrCountMap}
+		"end": endDateTimeStr, "counts": closedPrCountMap,
+		"byOutcome": map[string]interface{}{"merged": mergedPrCountMap, "closedWithoutMerge": closedWithoutMergeCountMap},
+		"mergeRate": mergeRateMap,
+		"externalContributors": map[string]interface{}{"count": externalPrCount,
+			"byOutcome": map[string]interface{}{"merged": externalMergedPrCount,
+				"closedWithoutMerge": externalPrCount - externalMergedPrCount},
+			"mergeRate": getMergeRate(externalMergedPrCount, externalPrCount)}}
+}
+
+/*
+ * a utility function that returns the merge rate (the percentage of the closed PRs that
+ * were merged) for the input number of merged and closed PRs (or nil if no PRs were closed)
+ */
+func getMergeRate(numMerged int, numClosed int) interface{} {
+	if numClosed == 0 {
+		return nil
+	}
+	return math.Round(float64(numMerged)/float64(numClosed)*10000) / 100
 }
@@ -26,9 +26,10 @@ var (
 		Long: `Constructs a list (sorted by age) of the of the PRs in the named
 GitHub organization that were closed in the defined time window (skipping any PRs
 that include the 'backlog' label and only including PRs from repositories that are
-managed by the named team)`,
+managed by the named team); each PR in the list includes its outcome (merged
+or closed without being merged) and the user who closed (or merged) it`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(listClosedPrCount())
+			utils.DumpMapAsJSON(utils.GetResultsByGroup(listClosedPrCount))
 		},
 	}
 )
@@ -92,7 +93,7 @@ func listClosedPrCount() []map[string]interface{} {
 			firstPage := true
 			// and a few other variables that we'll use to query the system for results
 			var err error
-			var edges repo.PrSearchEdges
+			var edges repo.ClosedPrSearchEdges
 			var pageInfo cmd.PageInfo
 			// loop over the pages of results until we've reached the end of the list of closed
 			// PRs for this organization
@@ -101,9 +102,9 @@ func listClosedPrCount() []map[string]interface{} {
 				// run our query and add the data we want from the query results to the
 				// repositoryList map
 				if firstPage {
-					err = client.Query(context.Background(), &repo.FirstPrSearchQuery, vars)
+					err = client.Query(context.Background(), &repo.FirstClosedPrSearchQuery, vars)
 				} else {
-					err = client.Query(context.Background(), &repo.PrSearchQuery, vars)
+					err = client.Query(context.Background(), &repo.ClosedPrSearchQuery, vars)
 				}
 				if err != nil {
 					// Handle error.
@@ -113,20 +114,20 @@ func listClosedPrCount() []map[string]interface{} {
 				// grab out the list of edges and the page info from the results of our search
 				// and loop over the edges
 				if firstPage {
-					edges = repo.FirstPrSearchQuery.Search.Edges
-					pageInfo = repo.FirstPrSearchQuery.Search.PageInfo
-					// set firstPage to false so that we'll use the repo.PrSearchQuery struct
+					edges = repo.FirstClosedPrSearchQuery.Search.Edges
+					pageInfo = repo.FirstClosedPrSearchQuery.Search.PageInfo
+					// set firstPage to false so that we'll use the repo.ClosedPrSearchQuery struct
 					// (and it's "after" value) for subsequent queries
 					firstPage = false
 					fmt.Fprintf(os.Stderr, ".")
 				} else {
-					edges = repo.PrSearchQuery.Search.Edges
-					pageInfo = repo.PrSearchQuery.Search.PageInfo
+					edges = repo.ClosedPrSearchQuery.Search.Edges
+					pageInfo = repo.ClosedPrSearchQuery.Search.PageInfo
 					fmt.Fprintf(os.Stderr, ".")
 				}
 				for _, edge := range edges {
 					// define a variable to that references the pull request itself
-					pullRequest := edge.Node.PullRequest
+					pullRequest := edge.Node.ClosedPullRequest
 					// if the current repository is managed by the team we're interested in, then check to see
 					// if we should add this PR to our list of closed PRs
 					if len(pullRequest.Repository.Name) > 0 {
@@ -147,11 +148,11 @@ func listClosedPrCount() []map[string]interface{} {
 						}
 						// determine if this issue was created by an internal or external user
 						// (i.e., a member of the organization or not)
-						creatorIsMember := false
-						if pullRequest.AuthorAssociation == "OWNER" ||
-							pullRequest.AuthorAssociation == "MEMBER" ||
-							pullRequest.AuthorAssociation == "COLLABORATOR" {
-							creatorIsMember = true
+						creatorIsMember := pullRequest.CreatorIsMember()
+						// and determine the outcome for this PR (merged or closed without being merged)
+						outcome := "closedWithoutMerge"
+						if pullRequest.Merged {
+							outcome = "merged"
 						}
 						// get the list of assignees for this issue
 						assigneeList := []string{}
@@ -162,9 +163,13 @@ func listClosedPrCount() []map[string]interface{} {
 						// the time it was closed)
 						prAge := pullRequest.ClosedAt.Time.Sub(pullRequest.CreatedAt.Time)
 						closedPrList = append(closedPrList, map[string]interface{}{
-							"createdAt":       pullRequest.CreatedAt.Time,
+							"createdAt":       utils.InTimeZone(pullRequest.CreatedAt.Time),
 							"closed":          pullRequest.Closed,
-							"closedAt":        pullRequest.ClosedAt.Time,
+							"closedAt":        utils.InTimeZone(pullRequest.ClosedAt.Time),
+							"closedBy":        pullRequest.GetClosedBy(),
+							"merged":          pullRequest.Merged,
+							"mergedAt":        utils.InTimeZone(pullRequest.MergedAt.Time),
+							"outcome":         outcome,
 							"url":             pullRequest.Url,
 							"title":           pullRequest.Title,
 							"creator":         pullRequest.Author.Login,
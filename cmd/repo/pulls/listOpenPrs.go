@@ -29,7 +29,7 @@ GitHub organization in the defined time window (skipping any PRs that include
 the 'backlog' label and only including PRs from repositories that are managed
 by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(listOpenPrCount())
+			utils.DumpMapAsJSON(utils.GetResultsByGroup(listOpenPrCount))
 		},
 	}
 )
@@ -86,12 +86,14 @@ func listOpenPrCount() []map[string]interface{} {
 	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
 	// should we only count comments from immediate team members?
 	commentsFromTeamOnly := viper.GetBool("restrictToTeam")
-	teamMemberIds := []string{}
-	if !commentsFromTeamOnly {
-		// and the details for members of the corresponding team
-		_, teamMemberMap := utils.GetTeamMembers(teamName)
-		// and from that map, construct list of member logins for that team
-		teamMemberIds = utils.GetTeamMemberIds(teamMemberMap)
+	teamRoster := utils.TeamRoster{}
+	if commentsFromTeamOnly {
+		// and the details for members of the corresponding team (or teams, including any
+		// subteams that are rolled up into them, if we're gathering results for more than
+		// one team)
+		_, teamMemberMap := utils.GetTeamMembers()
+		// and from that map, construct the roster (by login) for that team
+		teamRoster = utils.GetTeamRoster(teamMemberMap)
 	}
 	// retrieve the reference time for our query window
 	startDateTime, endDateTime := utils.GetQueryTimeWindow()
@@ -200,9 +202,9 @@ func listOpenPrCount() []map[string]interface{} {
 						}
 						// create a map to hold the data for this issue
 						prData := map[string]interface{}{
-							"createdAt":       pullRequest.CreatedAt.Time,
+							"createdAt":       utils.InTimeZone(pullRequest.CreatedAt.Time),
 							"closed":          pullRequest.Closed,
-							"closedAt":        pullRequest.ClosedAt.Time,
+							"closedAt":        utils.InTimeZone(pullRequest.ClosedAt.Time),
 							"url":             pullRequest.Url,
 							"title":           pullRequest.Title,
 							"creator":         pullRequest.Author.Login,
@@ -215,10 +217,10 @@ func listOpenPrCount() []map[string]interface{} {
 						// finally, if a flag was set to sort the list of issues by the first response
 						// time or staleness time, add that field to our output map
 						if sortByFirstResponse {
-							firstResponseTime := repo.GetFirstResponseTime(&pullRequest, endDateTime, commentsFromTeamOnly, teamMemberIds)
+							firstResponseTime := repo.GetFirstResponseTime(&pullRequest, endDateTime, commentsFromTeamOnly, teamRoster)
 							prData["firstResponseTime"] = utils.JsonDuration{Duration: firstResponseTime}
 						} else if sortByStaleness {
-							stalenessTime := repo.GetLatestResponseTime(&pullRequest, endDateTime, commentsFromTeamOnly, teamMemberIds)
+							stalenessTime := repo.GetLatestResponseTime(&pullRequest, endDateTime, commentsFromTeamOnly, teamRoster)
 							prData["staleness"] = utils.JsonDuration{Duration: stalenessTime}
 						}
 						// and add the issue to the list of open issues
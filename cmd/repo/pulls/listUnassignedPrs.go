@@ -28,7 +28,7 @@ unassigned in the named GitHub organization and defined time window (skipping
 any PRs that include the 'backlog' label and only including PRs from the
 repositories that are managed by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(listUnassignedPrCount())
+			utils.DumpMapAsJSON(utils.GetResultsByGroup(listUnassignedPrCount))
 		},
 	}
 )
@@ -174,9 +174,9 @@ func listUnassignedPrCount() []map[string]interface{} {
 							prAge = endDateTime.Sub(pullRequest.CreatedAt.Time)
 						}
 						unassignedPrList = append(unassignedPrList, map[string]interface{}{
-							"createdAt":       pullRequest.CreatedAt.Time,
+							"createdAt":       utils.InTimeZone(pullRequest.CreatedAt.Time),
 							"closed":          pullRequest.Closed,
-							"closedAt":        pullRequest.ClosedAt.Time,
+							"closedAt":        utils.InTimeZone(pullRequest.ClosedAt.Time),
 							"url":             pullRequest.Url,
 							"title":           pullRequest.Title,
 							"creator":         pullRequest.Author.Login,
@@ -0,0 +1,187 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package pulls
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// getCycleTimeStatsCmd represents the 'repo pulls cycleTime' command
+var (
+	getCycleTimeStatsCmd = &cobra.Command{
+		Use:   "cycleTime",
+		Short: "Statistics for each phase of the 'cycle time' of merged PRs",
+		Long: `Splits the lifecycle of each PR merged in the named GitHub organizations
+and in the defined time window into phases (the time spent as a draft,
+waiting for a first review, from the first review to approval, and from
+approval to merge) and calculates the statistics for each of those phases
+(skipping any PRs that include the 'backlog' label and only counting PRs
+in repositories that are managed by the named team)`,
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getCycleTimeStats))
+		},
+	}
+)
+
+func init() {
+	repo.PullsCmd.AddCommand(getCycleTimeStatsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	getCycleTimeStatsCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("comparePrevious", getCycleTimeStatsCmd.Flags().Lookup("compare-previous"))
+}
+
+/*
+ * define the function that is used to calculate the statistics associated with each
+ * of the phases of the "cycle time" for the PRs merged in the named GitHub organization(s);
+ * note that this function skips PRs that include the 'backlog' label and only includes
+ * PRs in repositories that are managed by the named team(s)
+ */
+func getCycleTimeStats(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
+	// initialize the vars map that we'll use when making our query for merged PRs
+	vars := map[string]interface{}{}
+	vars["first"] = githubv4.Int(100)
+	vars["type"] = githubv4.SearchTypeIssue
+	// next, retrieve the list of repositories that are managed by the team we're looking for
+	teamName, repositoryList := utils.GetTeamRepos()
+	// save date and datetime strings for use in output (below)
+	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
+	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
+	startDateTimeStr := startDateTime.Format(cmd.ISO8601_FormatStr)
+	endDateTimeStr := endDateTime.Format(cmd.ISO8601_FormatStr)
+	// and initialize a map of phase names to the slices of durations that will be used
+	// to store the time spent in each phase, along with a couple of counters for the PRs
+	// that were merged without being reviewed or approved
+	phaseTimeLists := map[string][]time.Duration{}
+	for _, phaseName := range repo.CycleTimePhaseNames {
+		phaseTimeLists[phaseName] = []time.Duration{}
+	}
+	numMergedPrs := 0
+	numWithoutReview := 0
+	numWithoutApproval := 0
+	// loop over the input organization names
+	for _, orgName := range utils.GetOrgNameList() {
+		// define the query to run for each organization; this query looks for PRs
+		// that were merged within the defined time window
+		vars["query"] = githubv4.String(fmt.Sprintf("org:%s type:pr is:merged -label:backlog merged:%s..%s", orgName,
+			startDateTimeStr, endDateTimeStr))
+		// initialize the flag that we use to determine if we're trying to retrieve
+		// the first page of results for this query (or not)
+		firstPage := true
+		// and a few other variables that we'll use to query the system for results
+		var err error
+		var edges repo.CycleTimePrSearchEdges
+		var pageInfo cmd.PageInfo
+		// loop over the pages of results from this query until we've reached the end
+		// of the list of PRs that matched
+		for {
+			// run our query and add the data we want from the query results to the
+			// repositoryList map
+			if firstPage {
+				err = client.Query(context.Background(), &repo.FirstCycleTimePrSearchQuery, vars)
+			} else {
+				err = client.Query(context.Background(), &repo.CycleTimePrSearchQuery, vars)
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			// grab out the list of edges and the page info from the results of our search
+			// and loop over the edges
+			if firstPage {
+				edges = repo.FirstCycleTimePrSearchQuery.Search.Edges
+				pageInfo = repo.FirstCycleTimePrSearchQuery.Search.PageInfo
+				// set firstPage to false so that we'll use the repo.CycleTimePrSearchQuery
+				// struct (and it's "after" value) for subsequent queries
+				firstPage = false
+				fmt.Fprintf(os.Stderr, ".")
+			} else {
+				edges = repo.CycleTimePrSearchQuery.Search.Edges
+				pageInfo = repo.CycleTimePrSearchQuery.Search.PageInfo
+				fmt.Fprintf(os.Stderr, ".")
+			}
+			for _, edge := range edges {
+				// define a variable to that references the pull request itself
+				pullRequest := edge.Node.CycleTimePullRequest
+				// if the current repository is managed by the team we're interested in, split
+				// the lifecycle of this PR into phases and save the time spent in each
+				if len(pullRequest.Repository.Name) > 0 {
+					orgAndRepoName := orgName + "/" + pullRequest.Repository.Name
+					idx := utils.FindIndexOf(orgAndRepoName, repositoryList)
+					// if the current repository is not managed by the team we're interested in, skip it
+					if idx < 0 {
+						continue
+					}
+					// if the repository associated with this PR is private and we're excluding
+					// private repositories or if it is archived, then skip it
+					if (excludePrivateRepos && pullRequest.Repository.IsPrivate) || pullRequest.Repository.IsArchived {
+						continue
+					}
+					numMergedPrs++
+					phases := repo.GetCycleTimePhases(&pullRequest)
+					for phaseName, phaseTime := range phases {
+						phaseTimeLists[phaseName] = append(phaseTimeLists[phaseName], phaseTime)
+					}
+					// and keep track of the PRs that were merged without a review or approval
+					if _, ok := phases["waitingForFirstReview"]; !ok {
+						numWithoutReview++
+					}
+					if _, ok := phases["approvalToMerge"]; !ok {
+						numWithoutApproval++
+					}
+				}
+			}
+			// if we've reached the end of the list of PRs, break out of the loop
+			if !pageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = pageInfo.EndCursor
+		}
+		// and unset the "after" key in the vars map so that we're ready
+		// for the next query
+		delete(vars, "after")
+	} // end of loop over organizations
+
+	// calculate the stats for each of the phases
+	phaseStats := map[string]interface{}{}
+	for _, phaseName := range repo.CycleTimePhaseNames {
+		stats, seriesLength := utils.GetJsonDurationStats(phaseTimeLists[phaseName])
+		phaseStats[phaseName] = map[string]interface{}{"seriesLength": seriesLength, "stats": stats}
+	}
+	// print a message indicating how many merged PRs were found
+	if numMergedPrs == 0 {
+		fmt.Fprintf(os.Stderr, "\nWARN: No merged PRs found for the specified organization(s)\n")
+	} else {
+		fmt.Fprintf(os.Stderr, "\nFound %d merged PRs in repositories managed by the '%s' team between %s and %s\n", numMergedPrs,
+			teamName, startDateStr, endDateStr)
+	}
+	// add return the results as a map
+	return map[string]interface{}{"title": "PR Cycle Time", "start": startDateTimeStr,
+		"end": endDateTimeStr, "seriesLength": numMergedPrs, "mergedWithoutReview": numWithoutReview,
+		"mergedWithoutApproval": numWithoutApproval, "phases": phaseStats}
+}
@@ -0,0 +1,248 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package pulls
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/cmd/repo"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// define the names of the size buckets that PRs are sorted into, along with the default
+// thresholds (in lines changed) that separate those buckets
+var (
+	sizeBucketNames             = []string{"XS", "S", "M", "L", "XL"}
+	defaultSizeBucketThresholds = []int{10, 100, 500, 1000}
+)
+
+// getPrSizeStatsCmd represents the 'repo pulls size' command
+var (
+	getPrSizeStatsCmd = &cobra.Command{
+		Use:   "size",
+		Short: "Statistics for the size of merged PRs",
+		Long: `Calculates the statistics for the size (lines added, lines deleted, lines
+changed, files changed, and commits) of the PRs merged in the named GitHub
+organizations and in the defined time window, and sorts those PRs into
+size buckets (XS through XL, based on the number of lines changed) with
+the median time to first review and time to merge for each bucket
+(skipping any PRs that include the 'backlog' label and only counting PRs
+in repositories that are managed by the named team)`,
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getPrSizeStats))
+		},
+	}
+)
+
+func init() {
+	repo.PullsCmd.AddCommand(getPrSizeStatsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	getPrSizeStatsCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("comparePrevious", getPrSizeStatsCmd.Flags().Lookup("compare-previous"))
+}
+
+/*
+ * retrieve the thresholds (in lines changed) that separate the size buckets from the
+ * 'pr_size_thresholds' configuration value (or the default thresholds if that value isn't
+ * defined); there should be one less threshold than there are buckets, in ascending order
+ */
+func getSizeBucketThresholds() []int {
+	configList := utils.GetConfigStringList("pr_size_thresholds")
+	if configList == nil {
+		return defaultSizeBucketThresholds
+	}
+	if len(configList) != len(sizeBucketNames)-1 {
+		fmt.Fprintf(os.Stderr, "ERROR: expected %d PR size thresholds (for the %v buckets); found %d\n",
+			len(sizeBucketNames)-1, sizeBucketNames, len(configList))
+		os.Exit(-9)
+	}
+	thresholds := []int{}
+	for idx, item := range configList {
+		threshold, err := strconv.Atoi(item)
+		if err != nil || threshold <= 0 || (idx > 0 && threshold <= thresholds[idx-1]) {
+			fmt.Fprintf(os.Stderr, "ERROR: invalid PR size threshold '%s'; expected increasing positive integers\n", item)
+			os.Exit(-9)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds
+}
+
+/*
+ * define the function that is used to calculate the statistics associated with the
+ * size of the PRs merged in the named GitHub organization(s); note that this function
+ * skips PRs that include the 'backlog' label and only includes PRs in repositories
+ * that are managed by the named team(s)
+ */
+func getPrSizeStats(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
+	// initialize the vars map that we'll use when making our query for merged PRs
+	vars := map[string]interface{}{}
+	vars["first"] = githubv4.Int(100)
+	vars["type"] = githubv4.SearchTypeIssue
+	// next, retrieve the list of repositories that are managed by the team we're looking for
+	teamName, repositoryList := utils.GetTeamRepos()
+	// and the thresholds that separate our size buckets
+	thresholds := getSizeBucketThresholds()
+	// save date and datetime strings for use in output (below)
+	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
+	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
+	startDateTimeStr := startDateTime.Format(cmd.ISO8601_FormatStr)
+	endDateTimeStr := endDateTime.Format(cmd.ISO8601_FormatStr)
+	// initialize the slices that will be used to store the size values for each PR
+	additionsList := []float64{}
+	deletionsList := []float64{}
+	linesChangedList := []float64{}
+	changedFilesList := []float64{}
+	commitsList := []float64{}
+	// along with the counts, times to first review, and times to merge for each bucket
+	bucketCounts := make([]int, len(sizeBucketNames))
+	bucketFirstReviewTimes := make([][]time.Duration, len(sizeBucketNames))
+	bucketMergeTimes := make([][]time.Duration, len(sizeBucketNames))
+	// loop over the input organization names
+	for _, orgName := range utils.GetOrgNameList() {
+		// define the query to run for each organization; this query looks for PRs
+		// that were merged within the defined time window
+		vars["query"] = githubv4.String(fmt.Sprintf("org:%s type:pr is:merged -label:backlog merged:%s..%s", orgName,
+			startDateTimeStr, endDateTimeStr))
+		// initialize the flag that we use to determine if we're trying to retrieve
+		// the first page of results for this query (or not)
+		firstPage := true
+		// and a few other variables that we'll use to query the system for results
+		var err error
+		var edges repo.CycleTimePrSearchEdges
+		var pageInfo cmd.PageInfo
+		// loop over the pages of results from this query until we've reached the end
+		// of the list of PRs that matched
+		for {
+			// run our query and add the data we want from the query results to the
+			// repositoryList map
+			if firstPage {
+				err = client.Query(context.Background(), &repo.FirstCycleTimePrSearchQuery, vars)
+			} else {
+				err = client.Query(context.Background(), &repo.CycleTimePrSearchQuery, vars)
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			// grab out the list of edges and the page info from the results of our search
+			// and loop over the edges
+			if firstPage {
+				edges = repo.FirstCycleTimePrSearchQuery.Search.Edges
+				pageInfo = repo.FirstCycleTimePrSearchQuery.Search.PageInfo
+				// set firstPage to false so that we'll use the repo.CycleTimePrSearchQuery
+				// struct (and it's "after" value) for subsequent queries
+				firstPage = false
+				fmt.Fprintf(os.Stderr, ".")
+			} else {
+				edges = repo.CycleTimePrSearchQuery.Search.Edges
+				pageInfo = repo.CycleTimePrSearchQuery.Search.PageInfo
+				fmt.Fprintf(os.Stderr, ".")
+			}
+			for _, edge := range edges {
+				// define a variable to that references the pull request itself
+				pullRequest := edge.Node.CycleTimePullRequest
+				// if the current repository is managed by the team we're interested in, save
+				// the size of this PR and add it to the appropriate size bucket
+				if len(pullRequest.Repository.Name) > 0 {
+					orgAndRepoName := orgName + "/" + pullRequest.Repository.Name
+					idx := utils.FindIndexOf(orgAndRepoName, repositoryList)
+					// if the current repository is not managed by the team we're interested in, skip it
+					if idx < 0 {
+						continue
+					}
+					// if the repository associated with this PR is private and we're excluding
+					// private repositories or if it is archived, then skip it
+					if (excludePrivateRepos && pullRequest.Repository.IsPrivate) || pullRequest.Repository.IsArchived {
+						continue
+					}
+					linesChanged := pullRequest.GetLinesChanged()
+					additionsList = append(additionsList, float64(pullRequest.Additions))
+					deletionsList = append(deletionsList, float64(pullRequest.Deletions))
+					linesChangedList = append(linesChangedList, float64(linesChanged))
+					changedFilesList = append(changedFilesList, float64(pullRequest.ChangedFiles))
+					commitsList = append(commitsList, float64(pullRequest.Commits.TotalCount))
+					// the bucket for this PR is determined by the number of thresholds that
+					// the number of lines changed meets or exceeds
+					bucketIdx := 0
+					for bucketIdx < len(thresholds) && linesChanged >= thresholds[bucketIdx] {
+						bucketIdx++
+					}
+					bucketCounts[bucketIdx]++
+					// the time to first review is the time this PR spent waiting for its first
+					// review once it was ready for review (if it was reviewed at all)
+					if firstReviewTime, ok := repo.GetCycleTimePhases(&pullRequest)["waitingForFirstReview"]; ok {
+						bucketFirstReviewTimes[bucketIdx] = append(bucketFirstReviewTimes[bucketIdx], firstReviewTime)
+					}
+					bucketMergeTimes[bucketIdx] = append(bucketMergeTimes[bucketIdx], pullRequest.MergedAt.Sub(pullRequest.CreatedAt.Time))
+				}
+			}
+			// if we've reached the end of the list of PRs, break out of the loop
+			if !pageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = pageInfo.EndCursor
+		}
+		// and unset the "after" key in the vars map so that we're ready
+		// for the next query
+		delete(vars, "after")
+	} // end of loop over organizations
+
+	// calculate the stats for each of our size values
+	additionsStats, numMergedPrs := utils.GetNumericStats(additionsList)
+	deletionsStats, _ := utils.GetNumericStats(deletionsList)
+	linesChangedStats, _ := utils.GetNumericStats(linesChangedList)
+	changedFilesStats, _ := utils.GetNumericStats(changedFilesList)
+	commitsStats, _ := utils.GetNumericStats(commitsList)
+	// and construct the output for each of our size buckets
+	buckets := []map[string]interface{}{}
+	for idx, bucketName := range sizeBucketNames {
+		bucket := map[string]interface{}{"size": bucketName, "count": bucketCounts[idx],
+			"reviewedCount":           len(bucketFirstReviewTimes[idx]),
+			"medianTimeToFirstReview": utils.JsonDuration{Duration: utils.GetMedianDuration(bucketFirstReviewTimes[idx])},
+			"medianTimeToMerge":       utils.JsonDuration{Duration: utils.GetMedianDuration(bucketMergeTimes[idx])}}
+		if idx > 0 {
+			bucket["from"] = thresholds[idx-1]
+		}
+		if idx < len(thresholds) {
+			bucket["to"] = thresholds[idx]
+		}
+		buckets = append(buckets, bucket)
+	}
+	// print a message indicating how many merged PRs were found
+	if numMergedPrs == 0 {
+		fmt.Fprintf(os.Stderr, "\nWARN: No merged PRs found for the specified organization(s)\n")
+	} else {
+		fmt.Fprintf(os.Stderr, "\nFound %d merged PRs in repositories managed by the '%s' team between %s and %s\n", numMergedPrs,
+			teamName, startDateStr, endDateStr)
+	}
+	// add return the results as a map
+	return map[string]interface{}{"title": "PR Size", "start": startDateTimeStr,
+		"end": endDateTimeStr, "seriesLength": numMergedPrs, "stats": map[string]interface{}{
+			"additions": additionsStats, "deletions": deletionsStats, "linesChanged": linesChangedStats,
+			"changedFiles": changedFilesStats, "commits": commitsStats}, "buckets": buckets}
+}
@@ -28,7 +28,7 @@ for all closed PRs in the named GitHub organizations and in the defined
 time window (skipping any PRs that include the 'backlog' label and only
 counting PRs in repositories that are managed by the named team)`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(getStalenessStats())
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getStalenessStats))
 		},
 	}
 )
@@ -44,9 +44,11 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	getStalenessStatsCmd.Flags().BoolVarP(&repo.RestrictToTeam, "restrict-to-team", "r", false, "only count comments from immediate team members")
+	getStalenessStatsCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
 
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
 	viper.BindPFlag("restrictToTeam", getStalenessStatsCmd.Flags().Lookup("restrict-to-team"))
+	viper.BindPFlag("comparePrevious", getStalenessStatsCmd.Flags().Lookup("compare-previous"))
 }
 
 /*
@@ -56,7 +58,7 @@ func init() {
  * includes first response times for PRs in repositories that are managed by the
  * named team(s)
  */
-func getStalenessStats() map[string]interface{} {
+func getStalenessStats(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
 	// first, get a new GitHub GraphQL API client
 	client := utils.GetAuthenticatedClient()
 	// initialize the vars map that we'll use when making our queries for PRs
@@ -70,15 +72,15 @@ func getStalenessStats() map[string]interface{} {
 	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
 	// should we only count comments from immediate team members?
 	commentsFromTeamOnly := viper.GetBool("restrictToTeam")
-	teamMemberIds := []string{}
-	if !commentsFromTeamOnly {
-		// and the details for members of the corresponding team
-		_, teamMemberMap := utils.GetTeamMembers(teamName)
-		// and from that map, construct list of member logins for that team
-		teamMemberIds = utils.GetTeamMemberIds(teamMemberMap)
+	teamRoster := utils.TeamRoster{}
+	if commentsFromTeamOnly {
+		// and the details for members of the corresponding team (or teams, including any
+		// subteams that are rolled up into them, if we're gathering results for more than
+		// one team)
+		_, teamMemberMap := utils.GetTeamMembers()
+		// and from that map, construct the roster (by login) for that team
+		teamRoster = utils.GetTeamRoster(teamMemberMap)
 	}
-	// retrieve the reference time for our query window
-	startDateTime, endDateTime := utils.GetQueryTimeWindow()
 	// save date and datetime strings for use in output (below)
 	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
 	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
@@ -168,7 +170,7 @@ func getStalenessStats() map[string]interface{} {
 						}
 						// if we got this far, then the current repository is managed by the team we're interested in,
 						// so get the time of the latest response for this pull request and add it to the list
-						stalenessTime := repo.GetLatestResponseTime(&pullRequest, endDateTime, commentsFromTeamOnly, teamMemberIds)
+						stalenessTime := repo.GetLatestResponseTime(&pullRequest, endDateTime, commentsFromTeamOnly, teamRoster)
 						stalenessTimeList = append(stalenessTimeList, stalenessTime)
 					}
 				}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/shurcooL/githubv4"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/tjmcs/get-gh-info/cmd"
 	"github.com/tjmcs/get-gh-info/cmd/repo"
 	"github.com/tjmcs/get-gh-info/utils"
@@ -25,9 +26,11 @@ var (
 and maximum 'time to resolution' for all closed PRs in the named GitHub
 organizations and in the defined time window (skipping any PRs that include
 the 'backlog' label and only counting PRs in repositories that are managed
-by the named team)`,
+by the named team); the same statistics are also reported separately for
+merged PRs and PRs closed without being merged, for all PRs and for PRs
+from external contributors`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(getTimeToResStats())
+			utils.DumpMapAsJSON(utils.GetResultsWithComparison(getTimeToResStats))
 		},
 	}
 )
@@ -42,8 +45,10 @@ func init() {
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
+	getTimeToResStatsCmd.Flags().BoolVar(&cmd.ComparePrevious, "compare-previous", false, "compare with the preceding time window of equal length")
 
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("comparePrevious", getTimeToResStatsCmd.Flags().Lookup("compare-previous"))
 }
 
 /*
@@ -53,7 +58,7 @@ func init() {
  * includes first response times for PRs in repositories that are managed by the
  * named team(s)
  */
-func getTimeToResStats() map[string]interface{} {
+func getTimeToResStats(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) map[string]interface{} {
 	// first, get a new GitHub GraphQL API client
 	client := utils.GetAuthenticatedClient()
 	// initialize the vars map that we'll use when making our query for closed PRs
@@ -63,8 +68,6 @@ func getTimeToResStats() map[string]interface{} {
 	vars["orderCommentsBy"] = githubv4.IssueCommentOrder{Field: "UPDATED_AT", Direction: "ASC"}
 	// next, retrieve the list of repositories that are managed by the team we're looking for
 	teamName, repositoryList := utils.GetTeamRepos()
-	// define the start and end time of our query window
-	startDateTime, endDateTime := utils.GetQueryTimeWindow()
 	// save date and datetime strings for use in output (below)
 	startDateStr := startDateTime.Format(cmd.YearMonthDayFormatStr)
 	endDateStr := endDateTime.Format(cmd.YearMonthDayFormatStr)
@@ -73,6 +76,11 @@ func getTimeToResStats() map[string]interface{} {
 	// and initialize a slice of durations that will be used to store the time to first
 	// response values
 	resolutionTimeList := []time.Duration{}
+	// along with a map of slices of durations for each outcome (merged or closed without
+	// being merged), and a similar map for the PRs from external contributors (creators
+	// who are not members of the organization)
+	resolutionTimesByOutcome := map[string][]time.Duration{"merged": {}, "closedWithoutMerge": {}}
+	externalResolutionTimesByOutcome := map[string][]time.Duration{"merged": {}, "closedWithoutMerge": {}}
 	// loop over the input organization names
 	for _, orgName := range utils.GetOrgNameList() {
 		// define the query to run for each organization; this query looks for closed PRs
@@ -149,7 +157,17 @@ func getTimeToResStats() map[string]interface{} {
 							continue
 						}
 						// and append the difference (the resolution time) to the list of resolution times
-						resolutionTimeList = append(resolutionTimeList, prClosedAt.Time.Sub(prCreatedAt.Time))
+						resolutionTime := prClosedAt.Time.Sub(prCreatedAt.Time)
+						resolutionTimeList = append(resolutionTimeList, resolutionTime)
+						// and to the list of resolution times for this PR's outcome
+						outcome := "closedWithoutMerge"
+						if pullRequest.Merged {
+							outcome = "merged"
+						}
+						resolutionTimesByOutcome[outcome] = append(resolutionTimesByOutcome[outcome], resolutionTime)
+						if !pullRequest.CreatorIsMember() {
+							externalResolutionTimesByOutcome[outcome] = append(externalResolutionTimesByOutcome[outcome], resolutionTime)
+						}
 					}
 				}
 				// if we've reached the end of the list of contributions, break out of the loop
@@ -175,7 +193,29 @@ func getTimeToResStats() map[string]interface{} {
 		fmt.Fprintf(os.Stderr, "\nFound %d closed PRs in repositories managed by the '%s' team between %s and %s\n", numClosedPrs,
 			teamName, startDateStr, endDateStr)
 	}
+	// then calculate the same stats for each outcome (for all PRs and for the PRs from external
+	// contributors) along with the corresponding merge rates
+	byOutcome, numMergedPrs := getStatsByOutcome(resolutionTimesByOutcome)
+	externalByOutcome, numExternalMergedPrs := getStatsByOutcome(externalResolutionTimesByOutcome)
+	numExternalPrs := len(externalResolutionTimesByOutcome["merged"]) + len(externalResolutionTimesByOutcome["closedWithoutMerge"])
 	// add return the results as a map
 	return map[string]interface{}{"title": "PR Time to Resolution", "start": startDateTimeStr,
-		"end": endDateTimeStr, "seriesLength": numClosedPrs, "stats": prAgeStats}
+		"end": endDateTimeStr, "seriesLength": numClosedPrs, "stats": prAgeStats,
+		"byOutcome": byOutcome, "mergeRate": getMergeRate(numMergedPrs, numClosedPrs),
+		"externalContributors": map[string]interface{}{"seriesLength": numExternalPrs,
+			"byOutcome": externalByOutcome, "mergeRate": getMergeRate(numExternalMergedPrs, numExternalPrs)}}
+}
+
+/*
+ * a utility function that calculates the stats for each of the slices of durations in the
+ * input map of outcomes to durations, returning those stats (as a map) along with the number
+ * of merged PRs
+ */
+func getStatsByOutcome(timesByOutcome map[string][]time.Duration) (map[string]interface{}, int) {
+	statsByOutcome := map[string]interface{}{}
+	for outcome, durations := range timesByOutcome {
+		stats, seriesLength := utils.GetJsonDurationStats(durations)
+		statsByOutcome[outcome] = map[string]interface{}{"seriesLength": seriesLength, "stats": stats}
+	}
+	return statsByOutcome, len(timesByOutcome["merged"])
 }
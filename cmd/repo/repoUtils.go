@@ -4,8 +4,12 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package repo
 
 import (
+	"fmt"
+	"os"
+	"regexp"
 	"time"
 
+	"github.com/gobwas/glob"
 	"github.com/shurcooL/githubv4"
 	"github.com/tjmcs/get-gh-info/cmd"
 	"github.com/tjmcs/get-gh-info/utils"
@@ -32,12 +36,12 @@ func IsClosed[C IssueOrPullRequest](contrib C) bool {
  *         time to first response if no response is found
  *   - fromTeamOnly: a boolean flag that indicates whether or not we should only count comments
  *         from immediate team members
- *   - teamIds: a slice of strings that contains the GitHub IDs of the members of the team that
- *         owns the repository that contains the issue or pull request for which we want to get
- *         the time of the first response
+ *   - teamRoster: the roster for the team that owns the repository that contains the issue or
+ *         pull request for which we want to get the time of the first response; a comment only
+ *         counts as a response from the team if its author was on the team when it was made
  *
  */
-func GetFirstResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4.DateTime, fromTeamOnly bool, teamIds []string) time.Duration {
+func GetFirstResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4.DateTime, fromTeamOnly bool, teamRoster utils.TeamRoster) time.Duration {
 	// define a variable to hold the time of the first response
 	var firstRespTime time.Duration
 	// grab the time that this contribution created, the time when it was was closed
@@ -79,9 +83,9 @@ func GetFirstResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4.
 			// set, then only count comments from immediate team members
 			if fromTeamOnly {
 				// if here, looking only for comments only from immediate team members,
-				// so if this comment is not from an immediate team member skip it
-				idx := utils.FindIndexOf(comment.Author.Login, teamIds)
-				if idx < 0 {
+				// so if this comment is not from someone who was an immediate team member
+				// at the time it was made, skip it
+				if !teamRoster.IsMemberAt(comment.Author.Login, comment.CreatedAt.Time) {
 					continue
 				}
 			} else {
@@ -116,12 +120,12 @@ func GetFirstResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4.
  *         time to first response if no response is found
  *   - fromTeamOnly: a boolean flag that indicates whether or not we should only count comments
  *         from immediate team members
- *   - teamIds: a slice of strings that contains the GitHub IDs of the members of the team that
- *         owns the repository that contains the issue or pull request for which we want to get
- *         the time of the first response
+ *   - teamRoster: the roster for the team that owns the repository that contains the issue or
+ *         pull request for which we want to get the time of the first response; a comment only
+ *         counts as a response from the team if its author was on the team when it was made
  *
  */
-func GetLatestResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4.DateTime, fromTeamOnly bool, teamIds []string) time.Duration {
+func GetLatestResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4.DateTime, fromTeamOnly bool, teamRoster utils.TeamRoster) time.Duration {
 	// grab a few values from this contribution that we'll need later
 	contribCreatedAt := contrib.GetCreatedAt()
 	contribIsClosed := contrib.IsClosed()
@@ -159,9 +163,9 @@ func GetLatestResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4
 			// set, then only count comments from immediate team members
 			if fromTeamOnly {
 				// if here, looking only for comments only from immediate team members,
-				// so if this comment is not from an immediate team member skip it
-				idx := utils.FindIndexOf(comment.Author.Login, teamIds)
-				if idx < 0 {
+				// so if this comment is not from someone who was an immediate team member
+				// at the time it was made, skip it
+				if !teamRoster.IsMemberAt(comment.Author.Login, comment.CreatedAt.Time) {
 					continue
 				}
 			} else {
@@ -193,3 +197,143 @@ func GetLatestResponseTime[C IssueOrPullRequest](contrib C, endDateTime githubv4
 	// didn't find one)
 	return stalenessTime
 }
+
+// the names of the phases returned by the GetCycleTimePhases function (below), in order
+var CycleTimePhaseNames = []string{"draft", "waitingForFirstReview", "reviewToApproval", "approvalToMerge"}
+
+/*
+ * Define a function that we can use to split the lifecycle of a (merged) pull request into
+ * a set of phases, using the events in that pull request's timeline. The phases returned
+ * (as a map of phase names to durations) are as follows:
+ *
+ *   - draft: the total time the PR spent as a draft, i.e. from when it was created (if it
+ *         was created as a draft) until it was first marked as ready for review, plus the
+ *         time between each later conversion back to a draft and the point at which it was
+ *         marked as ready for review again (only included for PRs that were drafts at
+ *         some point)
+ *   - waitingForFirstReview: the time from when the PR was first ready for review until the
+ *         first review was submitted by someone other than the author of the PR
+ *   - reviewToApproval: the time from that first review until the PR was first approved
+ *   - approvalToMerge: the time from when the PR was first approved until it was merged
+ *
+ * Phases that the PR never reached (e.g. a PR that was merged without being reviewed or
+ * approved) are left out of the map that is returned
+ */
+func GetCycleTimePhases(pullRequest *CycleTimePullRequest) map[string]time.Duration {
+	phases := map[string]time.Duration{}
+	authorLogin := pullRequest.Author.Login
+	// first, find the time at which the PR was first ready for review, the total time it
+	// spent as a draft, and the times at which it was first reviewed (by someone other than
+	// the author), first approved, and merged (note that the timeline items are returned in
+	// chronological order)
+	var readyAt, draftStartedAt, firstReviewAt, firstApprovalAt, mergedAt time.Time
+	var draftTime time.Duration
+	wasDraft := false
+	for _, item := range pullRequest.TimelineItems.Nodes {
+		switch item.Typename {
+		case "ConvertToDraftEvent":
+			// if this is the first draft-related event, then the PR was created as a
+			// regular PR (and it was ready for review when it was created)
+			if !wasDraft {
+				readyAt = pullRequest.CreatedAt.Time
+				wasDraft = true
+			}
+			if draftStartedAt.IsZero() {
+				draftStartedAt = item.ConvertToDraftEvent.CreatedAt.Time
+			}
+		case "ReadyForReviewEvent":
+			// if this is the first draft-related event, then the PR was created as a
+			// draft (and it stayed a draft until now)
+			if !wasDraft {
+				readyAt = item.ReadyForReviewEvent.CreatedAt.Time
+				draftStartedAt = pullRequest.CreatedAt.Time
+				wasDraft = true
+			}
+			if !draftStartedAt.IsZero() {
+				draftTime += item.ReadyForReviewEvent.CreatedAt.Sub(draftStartedAt)
+				draftStartedAt = time.Time{}
+			}
+		case "PullRequestReview":
+			review := item.PullRequestReview
+			// skip pending reviews (which haven't been submitted yet) and reviews
+			// made by the author of the PR
+			if review.SubmittedAt.IsZero() || review.State == "PENDING" || review.Author.Login == authorLogin {
+				continue
+			}
+			if firstReviewAt.IsZero() {
+				firstReviewAt = review.SubmittedAt.Time
+			}
+			if firstApprovalAt.IsZero() && review.State == "APPROVED" {
+				firstApprovalAt = review.SubmittedAt.Time
+			}
+		case "MergedEvent":
+			mergedAt = item.MergedEvent.CreatedAt.Time
+		}
+	}
+	if mergedAt.IsZero() {
+		mergedAt = pullRequest.MergedAt.Time
+	}
+	// a PR can't be merged while it's a draft, so if we didn't see it being marked as ready
+	// for review after it was last converted to a draft (e.g. because its timeline was
+	// truncated), then count the time up to when it was merged
+	if !draftStartedAt.IsZero() && mergedAt.After(draftStartedAt) {
+		draftTime += mergedAt.Sub(draftStartedAt)
+	}
+	// if the PR was never a draft, then it was ready for review when it was created
+	if wasDraft {
+		phases["draft"] = draftTime
+	} else {
+		readyAt = pullRequest.CreatedAt.Time
+	}
+	// if the PR wasn't reviewed, then there are no more phases to add
+	if firstReviewAt.IsZero() {
+		return phases
+	}
+	// a PR can be reviewed while it's still a draft, in which case there was no
+	// time spent waiting for the first review
+	if firstReviewAt.After(readyAt) {
+		phases["waitingForFirstReview"] = firstReviewAt.Sub(readyAt)
+	} else {
+		phases["waitingForFirstReview"] = 0
+	}
+	// and if the PR was approved, add the last two phases
+	if firstApprovalAt.IsZero() {
+		return phases
+	}
+	phases["reviewToApproval"] = firstApprovalAt.Sub(firstReviewAt)
+	if !mergedAt.IsZero() && mergedAt.After(firstApprovalAt) {
+		phases["approvalToMerge"] = mergedAt.Sub(firstApprovalAt)
+	} else if !mergedAt.IsZero() {
+		phases["approvalToMerge"] = 0
+	}
+	return phases
+}
+
+/*
+ * a utility function that returns a function that can be used to check whether a
+ * repository name matches the input search pattern; the pattern is interpreted as a
+ * glob-style pattern if the globStyle flag is set and as a regular expression if it
+ * isn't, and an empty pattern matches every repository name
+ */
+func GetRepoNameMatcher(pattern string, globStyle bool) func(string) bool {
+	// if no search pattern was specified, then every repository name matches
+	if pattern == "" {
+		return func(string) bool { return true }
+	}
+	// if the pattern is a glob-style pattern, then compile it as a glob-style pattern
+	if globStyle {
+		globPattern, err := glob.Compile(pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+			os.Exit(1)
+		}
+		return globPattern.Match
+	}
+	// otherwise compile it as a regular expression
+	searchRE, err := regexp.Compile(pattern)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+		os.Exit(1)
+	}
+	return searchRE.MatchString
+}
@@ -0,0 +1,142 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package repo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// the events in a PR's timeline that are used to build the PRs for the tests (below)
+type testTimelineEvent struct {
+	typename string
+	hour     int
+	author   string
+	state    string
+}
+
+// a utility function that appends a zero value to the input slice, returning the new slice
+// along with a pointer to the value that was appended (so that it can be filled in)
+func appendZero[T any](list []T) ([]T, *T) {
+	var zero T
+	list = append(list, zero)
+	return list, &list[len(list)-1]
+}
+
+/*
+ * a utility function that builds a (merged) PR that was created by the 'author' user at
+ * the start of the test day, and that has the input events in its timeline (the times of
+ * those events are given as the number of hours after the PR was created)
+ */
+func newTestCycleTimePullRequest(mergedHour int, events []testTimelineEvent) *CycleTimePullRequest {
+	createdAt := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	atHour := func(hour int) githubv4.DateTime {
+		return githubv4.DateTime{Time: createdAt.Add(time.Duration(hour) * time.Hour)}
+	}
+	pullRequest := &CycleTimePullRequest{CreatedAt: githubv4.DateTime{Time: createdAt}, MergedAt: atHour(mergedHour)}
+	pullRequest.Author.Login = "author"
+	for _, event := range events {
+		nodes, item := appendZero(pullRequest.TimelineItems.Nodes)
+		pullRequest.TimelineItems.Nodes = nodes
+		item.Typename = event.typename
+		switch event.typename {
+		case "ReadyForReviewEvent":
+			item.ReadyForReviewEvent.CreatedAt = atHour(event.hour)
+		case "ConvertToDraftEvent":
+			item.ConvertToDraftEvent.CreatedAt = atHour(event.hour)
+		case "PullRequestReview":
+			item.PullRequestReview.SubmittedAt = atHour(event.hour)
+			item.PullRequestReview.Author.Login = event.author
+			item.PullRequestReview.State = event.state
+		case "MergedEvent":
+			item.MergedEvent.CreatedAt = atHour(event.hour)
+		}
+	}
+	return pullRequest
+}
+
+func TestGetCycleTimePhases(t *testing.T) {
+	merged := func(hour int) testTimelineEvent { return testTimelineEvent{typename: "MergedEvent", hour: hour} }
+	ready := func(hour int) testTimelineEvent {
+		return testTimelineEvent{typename: "ReadyForReviewEvent", hour: hour}
+	}
+	toDraft := func(hour int) testTimelineEvent {
+		return testTimelineEvent{typename: "ConvertToDraftEvent", hour: hour}
+	}
+	review := func(hour int, author string, state string) testTimelineEvent {
+		return testTimelineEvent{typename: "PullRequestReview", hour: hour, author: author, state: state}
+	}
+	tests := []struct {
+		name       string
+		mergedHour int
+		events     []testTimelineEvent
+		expected   map[string]time.Duration
+	}{
+		{
+			name:       "merged without a review",
+			mergedHour: 5,
+			events:     []testTimelineEvent{merged(5)},
+			expected:   map[string]time.Duration{},
+		},
+		{
+			name:       "reviewed and approved (never a draft)",
+			mergedHour: 10,
+			events:     []testTimelineEvent{review(2, "reviewer", "COMMENTED"), review(6, "reviewer", "APPROVED"), merged(10)},
+			expected: map[string]time.Duration{"waitingForFirstReview": 2 * time.Hour, "reviewToApproval": 4 * time.Hour,
+				"approvalToMerge": 4 * time.Hour},
+		},
+		{
+			name:       "reviews by the author and pending reviews are skipped",
+			mergedHour: 10,
+			events: []testTimelineEvent{review(1, "author", "COMMENTED"), review(2, "reviewer", "PENDING"),
+				review(3, "reviewer", "APPROVED"), merged(10)},
+			expected: map[string]time.Duration{"waitingForFirstReview": 3 * time.Hour, "reviewToApproval": 0,
+				"approvalToMerge": 7 * time.Hour},
+		},
+		{
+			name:       "created as a draft",
+			mergedHour: 12,
+			events:     []testTimelineEvent{ready(4), review(6, "reviewer", "APPROVED"), merged(12)},
+			expected: map[string]time.Duration{"draft": 4 * time.Hour, "waitingForFirstReview": 2 * time.Hour,
+				"reviewToApproval": 0, "approvalToMerge": 6 * time.Hour},
+		},
+		{
+			name:       "reviewed while still a draft",
+			mergedHour: 8,
+			events:     []testTimelineEvent{review(2, "reviewer", "COMMENTED"), ready(4), merged(8)},
+			expected:   map[string]time.Duration{"draft": 4 * time.Hour, "waitingForFirstReview": 0},
+		},
+		{
+			name:       "converted to a draft after being created as a regular PR",
+			mergedHour: 12,
+			events:     []testTimelineEvent{review(1, "reviewer", "CHANGES_REQUESTED"), toDraft(2), ready(5), review(8, "reviewer", "APPROVED"), merged(12)},
+			expected: map[string]time.Duration{"draft": 3 * time.Hour, "waitingForFirstReview": time.Hour,
+				"reviewToApproval": 7 * time.Hour, "approvalToMerge": 4 * time.Hour},
+		},
+		{
+			name:       "created as a draft and converted back to a draft later",
+			mergedHour: 20,
+			events:     []testTimelineEvent{ready(3), toDraft(6), ready(10), review(12, "reviewer", "APPROVED"), merged(20)},
+			expected: map[string]time.Duration{"draft": 7 * time.Hour, "waitingForFirstReview": 9 * time.Hour,
+				"reviewToApproval": 0, "approvalToMerge": 8 * time.Hour},
+		},
+		{
+			name:       "converted to a draft without a matching ready for review event",
+			mergedHour: 9,
+			events:     []testTimelineEvent{toDraft(5)},
+			expected:   map[string]time.Duration{"draft": 4 * time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pullRequest := newTestCycleTimePullRequest(tt.mergedHour, tt.events)
+			if actual := GetCycleTimePhases(pullRequest); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("GetCycleTimePhases() = %v; expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -4,13 +4,21 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 )
 
+// the annotation used to mark commands that can be run without a configuration file
+const ConfigOptionalAnnotation = "configOptional"
+
 // rootCmd represents the base command when called without any subcommands
 var (
 	// define variables used in subcommands to setup flags asociated with the time window
@@ -18,13 +26,22 @@ var (
 	LookbackTime  string
 	ReferenceDate string
 	CompleteWeeks bool
+	SinceDate     string
+	UntilDate     string
+	Period        string
 	// and a couple of others that are used in various subcommands
 	CompTeam        string
+	AllTeams        bool
 	RepoMappingFile string
+	RepoTags        string
+	ExcludeRepoTags string
+	GroupBy         string
 	// and some other global variables that are used locally to setup persistent flags
 	cfgFile    string
 	outputFile string
 	orgList    string
+	timeZone   string
+	profile    string
 
 	RootCmd = &cobra.Command{
 		Use:   "getGhInfo",
@@ -53,44 +70,192 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "configuration file to use")
 	RootCmd.PersistentFlags().StringVarP(&outputFile, "file", "f", "", "file/stream for output (defaults to stdout)")
 	RootCmd.PersistentFlags().StringVarP(&orgList, "org-list", "o", "", "list of orgs to gather information from")
+	RootCmd.PersistentFlags().StringVar(&timeZone, "tz", "", "time zone for time windows and output (eg. Asia/Singapore)")
+	RootCmd.PersistentFlags().StringVar(&profile, "profile", "", "named profile (from the configuration file) to use")
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
 	viper.BindPFlag("outputFile", RootCmd.PersistentFlags().Lookup("file"))
 	viper.BindPFlag("orgList", RootCmd.PersistentFlags().Lookup("org-list"))
+	viper.BindPFlag("timezone", RootCmd.PersistentFlags().Lookup("tz"))
+}
+
+// the environment variables that can be used to override the values defined in the
+// configuration file (or by default), along with the configuration key that each one
+// overrides and the flag that takes precedence over it when used on the command-line
+var envOverrides = []struct {
+	key      string
+	flagName string
+	envVar   string
+}{
+	{"orgList", "org-list", "GETGHINFO_ORG_LIST"},
+	{"teamName", "team", "GETGHINFO_TEAM"},
+	{"repoMappingFile", "repo-mapping-file", "GETGHINFO_REPO_MAPPING_FILE"},
+	{"timezone", "tz", "GETGHINFO_TIMEZONE"},
+}
+
+/*
+ * a utility function that applies the values from the (prefixed) environment variables
+ * that can be used to override values; since several commands bind their own flags to
+ * the same configuration key, an environment variable is only applied if the command
+ * being run doesn't have the corresponding flag or if that flag wasn't used
+ */
+func applyEnvOverrides() {
+	command, _, err := RootCmd.Find(os.Args[1:])
+	if err != nil {
+		return
+	}
+	for _, override := range envOverrides {
+		value, ok := os.LookupEnv(override.envVar)
+		if !ok || value == "" {
+			continue
+		}
+		if flag := command.Flags().Lookup(override.flagName); flag != nil && flag.Changed {
+			continue
+		}
+		viper.Set(override.key, value)
+	}
+}
+
+/*
+ * a utility function that returns the directory that the user's configuration file
+ * is kept in; this is the '$XDG_CONFIG_HOME' directory (if that variable is set to
+ * an absolute path) or the '$HOME/.config' directory (if it isn't)
+ */
+func GetUserConfigDir() string {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+		return configHome
+	}
+	home, err := os.UserHomeDir()
+	cobra.CheckErr(err)
+	return filepath.Join(home, ".config")
+}
+
+/*
+ * a utility function that returns the list of configuration files to look for (in
+ * order) when a configuration file isn't named explicitly (using the '-c, --config'
+ * flag or the GETGHINFO_CONFIG environment variable); the 'getGhInfo.yaml' file in the
+ * user's configuration directory is used if it exists, otherwise the 'config.yml' file
+ * in the current working directory is used
+ */
+func getConfigSearchPaths() []string {
+	configDir := GetUserConfigDir()
+	return []string{
+		filepath.Join(configDir, "getGhInfo.yaml"),
+		filepath.Join(configDir, "getGhInfo.yml"),
+		"config.yml",
+		"config.yaml",
+	}
+}
+
+/*
+ * a utility function that returns the name of the configuration file to use (or an
+ * empty string if none of the configuration files in our search path exist)
+ */
+func findConfigFile() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	if envConfigFile := os.Getenv("GETGHINFO_CONFIG"); envConfigFile != "" {
+		return envConfigFile
+	}
+	for _, configFile := range getConfigSearchPaths() {
+		if _, err := os.Stat(configFile); err == nil {
+			return configFile
+		}
+	}
+	return ""
+}
+
+/*
+ * a utility function that applies the named profile (if any) to the configuration;
+ * the profiles are defined under the 'profiles' key in the configuration file, and the
+ * values defined for the selected profile (eg. its 'orgs', 'teams', 'default_team', and
+ * 'default_repo_mapping') replace the values defined at the top-level of that file
+ */
+func applyProfile(configFile string, profileName string) {
+	profilesMap, ok := viper.Get("profiles").(map[string]interface{})
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ERROR: the profile '%s' was selected, but no 'profiles' are defined in the configuration file '%s'\n", profileName, configFile)
+		os.Exit(3)
+	}
+	// note that the keys in the configuration are case-insensitive
+	profile, ok := profilesMap[strings.ToLower(profileName)]
+	if !ok {
+		profileNames := []string{}
+		for name := range profilesMap {
+			profileNames = append(profileNames, name)
+		}
+		sort.Strings(profileNames)
+		fmt.Fprintf(os.Stderr, "ERROR: unrecognized profile '%s'; expected one of %v\n", profileName, profileNames)
+		os.Exit(3)
+	}
+	profileMap, ok := profile.(map[string]interface{})
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ERROR: the profile '%s' in the configuration file must be a map of configuration values\n", profileName)
+		os.Exit(3)
+	}
+	// rebuild the configuration from the values defined at the top-level of the file
+	// (read separately, so that the flags and environment variables aren't included),
+	// with the values from the selected profile replacing those values
+	fileConfig := viper.New()
+	fileConfig.SetConfigFile(configFile)
+	fileConfig.SetConfigType("yaml")
+	cobra.CheckErr(fileConfig.ReadInConfig())
+	settings := fileConfig.AllSettings()
+	delete(settings, "profiles")
+	for key, val := range profileMap {
+		settings[key] = val
+	}
+	settingsBytes, err := yaml.Marshal(settings)
+	cobra.CheckErr(err)
+	if err := viper.ReadConfig(bytes.NewReader(settingsBytes)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying profile '%s': %v\n", profileName, err)
+		os.Exit(3)
+	}
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	if cfgFile != "" {
-		// if a configuration file was passed in, use it
-		viper.SetConfigFile(cfgFile)
-		viper.SetConfigType("yaml")
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-		// Add a config with name "getGhInfo" (with or without extension)
-		// in the '$HOME/.config' directory to the search path
-		viper.SetConfigName("getGhInfo")
-		viper.SetConfigType("yaml")
-		viper.AddConfigPath(fmt.Sprintf("%s/.config", home))
-		// and add a config with the name "config" (with or without extension)
-		// in the current working directory to the search path (note that files
-		// added later take precedence over those added earlier)
-		viper.SetConfigName("config")
-		viper.AddConfigPath(".")
-	}
-
-	// read in environment variables that match
-	viper.AutomaticEnv()
+	// apply the (prefixed) environment variables that can be used to override values
+	applyEnvOverrides()
+	// find the configuration file to use
+	configFile := findConfigFile()
+	if configFile == "" {
+		if isConfigOptional() {
+			// the command we're running (eg. 'config init') doesn't need a configuration file
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error reading config file: no configuration file found; looked for %v\n", getConfigSearchPaths())
+		os.Exit(3)
+	}
+	viper.SetConfigFile(configFile)
+	viper.SetConfigType("yaml")
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
-	} else {
+	if err := viper.ReadInConfig(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
 		os.Exit(3)
 	}
+	// and apply the selected profile (if any)
+	profileName := profile
+	if profileName == "" {
+		profileName = os.Getenv("GETGHINFO_PROFILE")
+	}
+	if profileName != "" {
+		applyProfile(configFile, profileName)
+		fmt.Fprintf(os.Stderr, "Using config file: %s (profile '%s')\n", viper.ConfigFileUsed(), profileName)
+	} else {
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	}
+}
+
+/*
+ * a utility function that returns true if the command being run doesn't need a
+ * configuration file (i.e. if it is annotated with the ConfigOptionalAnnotation)
+ */
+func isConfigOptional() bool {
+	command, _, err := RootCmd.Find(os.Args[1:])
+	return err == nil && command.Annotations[ConfigOptionalAnnotation] == "true"
 }
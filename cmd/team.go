@@ -0,0 +1,33 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// teamCmd represents the 'team' command
+var (
+	TeamCmd = &cobra.Command{
+		Use:   "team",
+		Short: "Manage the team-related data",
+		Long:  "The subcommand used as the root for all commands that manage team-related data",
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(TeamCmd)
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	TeamCmd.PersistentFlags().StringVarP(&CompTeam, "team", "t", "", "name of team to gather data for")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("teamName", TeamCmd.PersistentFlags().Lookup("team"))
+}
@@ -0,0 +1,314 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package team
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// syncCmd represents the 'team sync' command
+var (
+	syncDiff              bool
+	syncIncludeChildTeams bool
+	syncCmd               = &cobra.Command{
+		Use:   "sync",
+		Short: "Synchronizes the team rosters with the teams in GitHub",
+		Long: `Reads the teams (and the members of those teams) defined in the named set of
+GitHub organizations and either outputs the 'teams' entries for those teams
+(in the form used in the configuration file) or, if the '--diff' flag is used,
+compares them with the teams that are already defined in the configuration
+file and reports the members that would be added to or removed from each.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			results := syncTeams()
+			if viper.GetBool("syncDiff") {
+				utils.DumpMapAsJSON(diffTeams(results))
+				return
+			}
+			utils.DumpText(teamsAsYAML(results))
+		},
+	}
+)
+
+func init() {
+	cmd.TeamCmd.AddCommand(syncCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	syncCmd.Flags().BoolVar(&syncDiff, "diff", false, "compare the GitHub teams with the teams in the configuration file")
+	syncCmd.Flags().BoolVar(&syncIncludeChildTeams, "include-child-teams", false, "include the members of child teams in each team")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("syncDiff", syncCmd.Flags().Lookup("diff"))
+	viper.BindPFlag("syncIncludeChildTeams", syncCmd.Flags().Lookup("include-child-teams"))
+}
+
+/*
+ * define a pair of structs that we'll use to retrieve the list of teams in an organization;
+ * the first is used to query for the first page of results and the second is used to query
+ * for subsequent pages of results
+ */
+type orgTeamsBody struct {
+	Nodes []struct {
+		Slug string
+	}
+	PageInfo cmd.PageInfo
+}
+
+var firstOrgTeamsQuery struct {
+	Organization struct {
+		Teams struct {
+			orgTeamsBody
+		} `graphql:"teams(first: $first)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+var orgTeamsQuery struct {
+	Organization struct {
+		Teams struct {
+			orgTeamsBody
+		} `graphql:"teams(first: $first, after: $after)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+// and a struct used to track each of the GitHub teams that we're synchronizing
+type gitHubTeam struct {
+	configName string
+	orgName    string
+	slug       string
+	members    []map[string]string
+}
+
+/*
+ * a utility function that returns the list of team slugs in the named organization
+ */
+func getOrgTeamSlugs(client *githubv4.Client, orgName string) []string {
+	vars := map[string]interface{}{
+		"orgname": githubv4.String(orgName),
+		"first":   githubv4.Int(100),
+	}
+	teamSlugs := []string{}
+	firstPage := true
+	for {
+		var err error
+		var teams orgTeamsBody
+		if firstPage {
+			err = client.Query(context.Background(), &firstOrgTeamsQuery, vars)
+			teams = firstOrgTeamsQuery.Organization.Teams.orgTeamsBody
+			firstPage = false
+		} else {
+			err = client.Query(context.Background(), &orgTeamsQuery, vars)
+			teams = orgTeamsQuery.Organization.Teams.orgTeamsBody
+		}
+		if err != nil {
+			// Handle error.
+			fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+			os.Exit(1)
+		}
+		for _, team := range teams.Nodes {
+			teamSlugs = append(teamSlugs, team.Slug)
+		}
+		// if we've reached the end of the list of teams, break out of the loop
+		if !teams.PageInfo.HasNextPage {
+			break
+		}
+		// set the "after" field to the "EndCursor" from the pageInfo structure so
+		// we will get the next page of results when we run the query again
+		vars["after"] = teams.PageInfo.EndCursor
+	}
+	return teamSlugs
+}
+
+/*
+ * a utility function that returns the names of the teams that are defined in the
+ * configuration file
+ */
+func getConfigTeamNames() []string {
+	teamNames := []string{}
+	if teamsMap, ok := viper.Get("teams").(map[string]interface{}); ok {
+		for teamName := range teamsMap {
+			teamNames = append(teamNames, teamName)
+		}
+	}
+	sort.Strings(teamNames)
+	return teamNames
+}
+
+/*
+ * a utility function that returns the name of the team in the configuration file that
+ * corresponds to the named GitHub team; by default the names are the same, but this can
+ * be overridden using the (optional) 'team_slugs' map in the configuration file, which
+ * maps the names of the teams in the configuration file to GitHub team slugs (either as
+ * 'slug' or as 'org/slug')
+ */
+func getConfigTeamName(orgName string, teamSlug string) string {
+	for configName, slug := range viper.GetStringMapString("team_slugs") {
+		if strings.EqualFold(slug, teamSlug) || strings.EqualFold(slug, orgName+"/"+teamSlug) {
+			return configName
+		}
+	}
+	return teamSlug
+}
+
+/*
+ * define the function that is used to retrieve the teams (and their members) from the
+ * named GitHub organization(s); if a team was named on the command-line, then only that
+ * team is retrieved
+ */
+func syncTeams() []gitHubTeam {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	immediateOnly := !viper.GetBool("syncIncludeChildTeams")
+	teamName := viper.GetString("teamName")
+	// if the team name is of the form '@org/slug', then we already know which GitHub team
+	// to retrieve; otherwise, look for the corresponding slug in the 'team_slugs' map
+	// (falling back to the team name itself)
+	wantedSlug := ""
+	if orgName, teamSlug, ok := utils.ParseGitHubTeamName(teamName); ok {
+		members := utils.GetGitHubTeamMembers(client, orgName, teamSlug, immediateOnly)
+		return []gitHubTeam{{configName: getConfigTeamName(orgName, teamSlug), orgName: orgName, slug: teamSlug, members: members}}
+	} else if teamName != "" {
+		wantedSlug = teamName
+		if slug, ok := viper.GetStringMapString("team_slugs")[teamName]; ok {
+			wantedSlug = slug
+		}
+	}
+	teams := []gitHubTeam{}
+	for _, orgName := range utils.GetOrgNameList() {
+		for _, teamSlug := range getOrgTeamSlugs(client, orgName) {
+			if wantedSlug != "" && !strings.EqualFold(wantedSlug, teamSlug) && !strings.EqualFold(wantedSlug, orgName+"/"+teamSlug) {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, ".")
+			teams = append(teams, gitHubTeam{
+				configName: getConfigTeamName(orgName, teamSlug),
+				orgName:    orgName,
+				slug:       teamSlug,
+				members:    utils.GetGitHubTeamMembers(client, orgName, teamSlug, immediateOnly),
+			})
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nFound %d teams\n", len(teams))
+	if wantedSlug != "" && len(teams) == 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to find a GitHub team with the slug '%s' for the team '%s'\n", wantedSlug, teamName)
+		os.Exit(-6)
+	}
+	// use the short names for any users that are already defined in the configuration file
+	// (so that the same user names can continue to be used with the '-u, --user-list' flag),
+	// along with the (primary) GitHub ID, aliases, and emails defined for those users (so
+	// that a user who is a member of the GitHub team under one of their aliases is still
+	// recognized as the same person)
+	knownUsers := map[string]map[string]string{}
+	for _, configName := range getConfigTeamNames() {
+		_, configMembers := utils.GetTeamMembers(configName)
+		for _, member := range configMembers {
+			for _, login := range utils.GetMemberLogins(member) {
+				knownUsers[strings.ToLower(login)] = member
+			}
+		}
+	}
+	for _, team := range teams {
+		for _, member := range team.members {
+			if knownUser, ok := knownUsers[strings.ToLower(member["githubid"])]; ok {
+				for _, key := range []string{"user", "githubid", "aliases", "emails"} {
+					if knownUser[key] != "" {
+						member[key] = knownUser[key]
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].configName < teams[j].configName
+	})
+	return teams
+}
+
+/*
+ * a function that formats the input teams as the 'teams' entry of a configuration file
+ */
+func teamsAsYAML(teams []gitHubTeam) string {
+	var buf bytes.Buffer
+	buf.WriteString("teams:\n")
+	for _, team := range teams {
+		fmt.Fprintf(&buf, "  # synchronized from the GitHub team '%s/%s'\n", team.orgName, team.slug)
+		fmt.Fprintf(&buf, "  %s:\n", utils.YamlScalar(team.configName))
+		for _, member := range team.members {
+			fmt.Fprintf(&buf, "    - %s\n", utils.TeamMemberAsYAML(member))
+		}
+	}
+	return buf.String()
+}
+
+/*
+ * a function that compares the input teams with the teams in the configuration file,
+ * returning the members that would be added to (or removed from) each of the teams
+ * that appear in both, along with the teams that only appear in one or the other
+ */
+func diffTeams(teams []gitHubTeam) map[string]interface{} {
+	configTeamNames := getConfigTeamNames()
+	byTeam := map[string]interface{}{}
+	notInConfig := []string{}
+	for _, team := range teams {
+		if !utils.SliceContains(configTeamNames, team.configName) {
+			notInConfig = append(notInConfig, team.configName)
+			continue
+		}
+		_, configMembers := utils.GetTeamMembers(team.configName)
+		configIds := map[string]bool{}
+		for _, member := range configMembers {
+			for _, login := range utils.GetMemberLogins(member) {
+				configIds[strings.ToLower(login)] = true
+			}
+		}
+		gitHubIds := map[string]bool{}
+		added := []map[string]string{}
+		for _, member := range team.members {
+			gitHubIds[strings.ToLower(member["githubid"])] = true
+			if !configIds[strings.ToLower(member["githubid"])] {
+				added = append(added, member)
+			}
+		}
+		removed := []map[string]string{}
+		for _, member := range configMembers {
+			if !gitHubIds[strings.ToLower(member["githubid"])] {
+				removed = append(removed, member)
+			}
+		}
+		byTeam[team.configName] = map[string]interface{}{
+			"gitHubTeam": team.orgName + "/" + team.slug,
+			"added":      added,
+			"removed":    removed,
+			"unchanged":  len(team.members) - len(added),
+			"inSync":     len(added) == 0 && len(removed) == 0,
+		}
+	}
+	// when all of the teams were retrieved, also list the teams in the configuration file
+	// that weren't found in GitHub (these may be groupings that only exist locally)
+	notInGitHub := []string{}
+	if viper.GetString("teamName") == "" {
+		for _, configName := range configTeamNames {
+			if _, ok := byTeam[configName]; !ok {
+				notInGitHub = append(notInGitHub, configName)
+			}
+		}
+	}
+	return map[string]interface{}{"title": "Team Roster Differences",
+		"byTeam": byTeam, "notInConfig": notInConfig, "notInGitHub": notInGitHub}
+}
@@ -31,7 +31,11 @@ func init() {
 	UserCmd.PersistentFlags().StringVarP(&ReferenceDate, "ref-date", "d", "", "reference date for time window (YYYY-MM-DD)")
 	UserCmd.PersistentFlags().StringVarP(&LookbackTime, "lookback-time", "l", "", "'lookback' time window (eg. 10d, 3w, 2m, 1q, 1y)")
 	UserCmd.PersistentFlags().BoolVarP(&CompleteWeeks, "complete-weeks", "w", false, "only output complete weeks (starting Monday)")
-	UserCmd.PersistentFlags().StringVarP(&CompTeam, "team", "t", "", "name of team to gather data for or compare against")
+	UserCmd.PersistentFlags().StringVar(&SinceDate, "since", "", "start date for time window (YYYY-MM-DD)")
+	UserCmd.PersistentFlags().StringVar(&UntilDate, "until", "", "end date (inclusive) for time window (YYYY-MM-DD)")
+	UserCmd.PersistentFlags().StringVar(&Period, "period", "", "named time window (eg. 2026-Q3, 2026-09, 2026-W37, last-quarter)")
+	UserCmd.PersistentFlags().StringVarP(&CompTeam, "team", "t", "", "name of team (or comma-separated list of teams) to gather data for or compare against")
+	UserCmd.PersistentFlags().BoolVar(&AllTeams, "all-teams", false, "gather data for each of the teams defined in the configuration file")
 
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
@@ -42,6 +46,10 @@ func init() {
 	viper.BindPFlag("referenceDate", UserCmd.PersistentFlags().Lookup("ref-date"))
 	viper.BindPFlag("lookbackTime", UserCmd.PersistentFlags().Lookup("lookback-time"))
 	viper.BindPFlag("completeWeeks", UserCmd.PersistentFlags().Lookup("complete-weeks"))
+	viper.BindPFlag("sinceDate", UserCmd.PersistentFlags().Lookup("since"))
+	viper.BindPFlag("untilDate", UserCmd.PersistentFlags().Lookup("until"))
+	viper.BindPFlag("period", UserCmd.PersistentFlags().Lookup("period"))
 	viper.BindPFlag("teamName", UserCmd.PersistentFlags().Lookup("team"))
+	viper.BindPFlag("allTeams", UserCmd.PersistentFlags().Lookup("all-teams"))
 
 }
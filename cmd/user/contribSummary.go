@@ -8,24 +8,34 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"strings"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/shurcooL/githubv4"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/tjmcs/get-gh-info/cmd"
 	"github.com/tjmcs/get-gh-info/utils"
 )
 
 // contribSummaryCmd represents the 'contribSummary' command
 var (
-	contribSummaryCmd = &cobra.Command{
+	baseline            string
+	rankStats           bool
+	excludeFromBaseline string
+	normalizeActiveDays bool
+	contribSummaryCmd   = &cobra.Command{
 		Use:   "contribSummary",
 		Short: "Generates a summary (including statistics) of contributions",
 		Long: `Constructs a summary (including statistics) of all of the contributions
 that each of the input users made to any repository to any of the repositories
-in the named set of GitHub organizations.`,
+in the named set of GitHub organizations; each user's contributions are compared
+with a baseline (the mean or median of the contributions made by the members of
+the comparison team, counting only the contributions made while each member was
+on the team, optionally excluding some members and normalising by the number of
+days each member was active on the team).`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(summaryOfContribs())
+			utils.DumpMapAsJSON(utils.GetResultsByTeam(summaryOfContribs))
 		},
 	}
 )
@@ -41,8 +51,47 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 
+	contribSummaryCmd.Flags().StringVar(&baseline, "baseline", "mean", "baseline used for team comparisons (mean or median)")
+	contribSummaryCmd.Flags().BoolVar(&rankStats, "rank-stats", false, "include percentile ranks and z-scores within the team")
+	contribSummaryCmd.Flags().StringVar(&excludeFromBaseline, "exclude-from-baseline", "", "list of GitHub IDs to exclude from the baseline")
+	contribSummaryCmd.Flags().BoolVar(&normalizeActiveDays, "normalize", false, "normalise contributions by each member's active days")
+
 	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("baseline", contribSummaryCmd.Flags().Lookup("baseline"))
+	viper.BindPFlag("rankStats", contribSummaryCmd.Flags().Lookup("rank-stats"))
+	viper.BindPFlag("excludeFromBaseline", contribSummaryCmd.Flags().Lookup("exclude-from-baseline"))
+	viper.BindPFlag("normalizeActiveDays", contribSummaryCmd.Flags().Lookup("normalize"))
+}
 
+/*
+ * define the metrics that are included in the summary for each user; for each metric
+ * we define the key used for the metric in the output, the key used for the comparison
+ * of that metric with the team average (as a percentage), and a function that pulls
+ * the value for the metric out of the contribution totals returned by our query
+ */
+type contribSummaryMetric struct {
+	key         string
+	teamPcntKey string
+	getValue    func(totals ContributionTotals) int
+}
+
+var contribSummaryMetrics = []contribSummaryMetric{
+	{"issueContribs", "teamPcntIssueContribs",
+		func(totals ContributionTotals) int { return totals.TotalIssueContributions }},
+	{"reposWithIssueContribs", "teamPcntReposWithContribIssues",
+		func(totals ContributionTotals) int { return totals.TotalRepositoriesWithContributedIssues }},
+	{"commitContribs", "teamPcntCommitContribs",
+		func(totals ContributionTotals) int { return totals.TotalCommitContributions }},
+	{"reposWithCommitContribs", "teamPcntReposWithContribCommits",
+		func(totals ContributionTotals) int { return totals.TotalRepositoriesWithContributedCommits }},
+	{"pullReqContribs", "teamPcntPullReqContribs",
+		func(totals ContributionTotals) int { return totals.TotalPullRequestContributions }},
+	{"reposWithPullReqContribs", "teamPcntReposWithContribPullReqs",
+		func(totals ContributionTotals) int { return totals.TotalRepositoriesWithContributedPullRequests }},
+	{"pullReqReviewContribs", "teamPcntPullReqReviewContribs",
+		func(totals ContributionTotals) int { return totals.TotalPullRequestReviewContributions }},
+	{"reposWithPullReqReviewsContribs", "teamPcntReposWithContribPullReqReviews",
+		func(totals ContributionTotals) int { return totals.TotalRepositoriesWithContributedPullRequestReviews }},
 }
 
 /*
@@ -52,7 +101,9 @@ func init() {
 func summaryOfContribs() map[string]interface{} {
 	// first, get a new GitHub GraphQL API client
 	client := utils.GetAuthenticatedClient()
-	// and then get the list of organization IDs that we want to query
+	// and then get the list of organization names and IDs that we want to query
+	// (the IDs are returned in the same order as the names)
+	orgNameList := utils.GetOrgNameList()
 	orgIdList := utils.GetOrgIdList(client)
 	// define the start and end time of our query window
 	startDateTime, endDateTime := utils.GetQueryTimeWindow()
@@ -71,79 +122,162 @@ func summaryOfContribs() map[string]interface{} {
 		mySet.Add(member["githubid"])
 	}
 	// initialize the vars map that we'll use when making our query for a summary of contributions
-	vars := map[string]interface{}{
-		"from": startDateTime,
-		"to":   endDateTime,
-	}
+	vars := map[string]interface{}{}
 	// and grab the GitHub IDs from that set as a slice
 	gitHubIdList := mySet.ToSlice()
-	// initialize a few variables
-	var avgPullReqContribs, avgReposWithContribPullReqs,
-		avgPullReqReviewContribs, avgReposWithContribPullReqReviews float64
+	// determine how the comparisons with the team should be made
+	baselineType := strings.ToLower(viper.GetString("baseline"))
+	if baselineType != "mean" && baselineType != "median" {
+		fmt.Fprintf(os.Stderr, "ERROR: unrecognized baseline '%s'; expected 'mean' or 'median'\n", baselineType)
+		os.Exit(-9)
+	}
+	// (any of a user's logins can be used to exclude that user from the baseline)
+	excludedIds := []string{}
+	for _, excludedId := range utils.GetConfigStringList("excludeFromBaseline") {
+		excludedIds = append(excludedIds, utils.GetPersonId(excludedId))
+	}
+	normalize := viper.GetBool("normalizeActiveDays")
+	windowDays := endDateTime.Sub(startDateTime.Time).Hours() / 24
+	// initialize a few variables (including a map of GitHub IDs to the totals for each
+	// metric, normalised by the number of days each user was active if requested)
+	valuesByUser := map[string]map[string]float64{}
+	baselineIds := []string{}
 	contribByUserSummary := map[string]interface{}{}
 	// loop over the list of GitHub IDs
 	for _, gitHubId := range gitHubIdList {
 		// convert the input value to a string
 		gitHubIdStr := gitHubId.(string)
-		// initialize a few variables
-		var totalPullReqContribs, totalReposWithContribPullReqs,
-			totalPullReqReviewContribs, totalReposWithContribPullReqReviews int
-		// set the login value for this query to the current user's GitHub ID
-		vars["login"] = githubv4.String(gitHubIdStr)
+		// initialize a map of metric keys to the totals for this user, along with a
+		// map that will hold the breakdown of those totals by organization
+		userTotals := map[string]int{}
+		userTotalsByOrg := map[string]interface{}{}
+		// the contributions made by each member of the team are only counted for the part
+		// of the time window that they were on the team, and only those members make up
+		// the baseline (users who aren't members of the team, or who weren't on the team
+		// at all during the time window, are counted for the whole time window but are
+		// left out of the baseline)
+		activeStart, activeEnd := startDateTime.Time, endDateTime.Time
+		inBaseline := false
+		for _, member := range teamList {
+			if member["githubid"] == gitHubIdStr {
+				memberStart, memberEnd := utils.GetMemberActiveWindow(member, startDateTime.Time, endDateTime.Time)
+				if memberEnd.After(memberStart) {
+					activeStart, activeEnd = memberStart, memberEnd
+					inBaseline = true
+				}
+			}
+		}
+		activeDays := activeEnd.Sub(activeStart).Hours() / 24
+		vars["from"] = githubv4.DateTime{Time: activeStart}
+		vars["to"] = githubv4.DateTime{Time: activeEnd}
 		// loop over the list of organization IDs and gather contribution
 		// information for this GitHub user for all of them
-		for _, orgId := range orgIdList {
+		for idx, orgId := range orgIdList {
 			// set the organization ID value for this query to the current
 			// orgId value
 			vars["organizationID"] = orgId
-			// and run our query, returning the results in the ContribQuery struct
-			err := client.Query(context.Background(), &ContribQuery, vars)
-			if err != nil {
-				// Handle error.
-				fmt.Fprintln(os.Stderr, err)
-				os.Exit(1)
+			orgTotals := map[string]int{}
+			// then loop over all of the GitHub logins for this user (for users with
+			// more than one GitHub account), adding up the results for all of them
+			for _, login := range utils.GetUserLogins(gitHubIdStr) {
+				// set the login value for this query to the current login
+				vars["login"] = githubv4.String(login)
+				// and run our query, returning the results in the ContribQuery struct
+				err := client.Query(context.Background(), &ContribQuery, vars)
+				if err != nil {
+					// Handle error.
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				// extract the ContributionsCollection part of the result
+				contributionsCollection := ContribQuery.User.ContributionsCollection
+				// and use it to accumulate the results for this user to the repositories
+				// in this organization
+				for _, metric := range contribSummaryMetrics {
+					orgTotals[metric.key] += metric.getValue(contributionsCollection)
+				}
+			}
+			// saving the results for this organization as we go
+			orgTotalsMap := map[string]interface{}{}
+			for _, metric := range contribSummaryMetrics {
+				userTotals[metric.key] += orgTotals[metric.key]
+				orgTotalsMap[metric.key] = orgTotals[metric.key]
+			}
+			userTotalsByOrg[orgNameList[idx]] = orgTotalsMap
+		}
+		// if we're normalising by the number of days each user was active on the team, then
+		// scale this user's totals up to the length of the time window before comparing them
+		// with the rest of the team
+		userValues := map[string]float64{}
+		if activeDays > 0 {
+			scale := 1.0
+			if normalize {
+				scale = windowDays / activeDays
+			}
+			for _, metric := range contribSummaryMetrics {
+				userValues[metric.key] = float64(userTotals[metric.key]) * scale
+			}
+			valuesByUser[gitHubIdStr] = userValues
+			if inBaseline {
+				baselineIds = append(baselineIds, gitHubIdStr)
 			}
-			// extract the ContributionsCollection part of the result
-			contributionsCollection := ContribQuery.User.ContributionsCollection
-			// and use it to accumulate the results for this user to the repositories
-			// in this organization
-			totalPullReqContribs += contributionsCollection.TotalPullRequestContributions
-			totalReposWithContribPullReqs += contributionsCollection.TotalRepositoriesWithContributedPullRequests
-			totalPullReqReviewContribs += contributionsCollection.TotalPullRequestReviewContributions
-			totalReposWithContribPullReqReviews += contributionsCollection.TotalRepositoriesWithContributedPullRequestReviews
 		}
 		// and add the contribution details for this user to the summary
 		// for the entire team
 		if utils.SliceContains(userIdList, gitHubIdStr) {
-			contribByUserSummary[gitHubIdStr] = map[string]interface{}{
-				"pullReqContribs":                 totalPullReqContribs,
-				"reposWithPullReqContribs":        totalReposWithContribPullReqs,
-				"pullReqReviewContribs":           totalPullReqReviewContribs,
-				"reposWithPullReqReviewsContribs": totalReposWithContribPullReqReviews,
+			userMap := map[string]interface{}{"byOrg": userTotalsByOrg}
+			for _, metric := range contribSummaryMetrics {
+				userMap[metric.key] = userTotals[metric.key]
 			}
+			if normalize {
+				userMap["activeDays"] = math.Round(activeDays*100) / 100
+				normalizedMap := map[string]interface{}{}
+				for key, val := range userValues {
+					normalizedMap[key] = math.Round(val*100) / 100
+				}
+				userMap["normalized"] = normalizedMap
+			}
+			contribByUserSummary[gitHubIdStr] = userMap
 		}
-		// add current user contributions (weighted by the number of input GitHub users)
-		// to determine the average for each metric for the team
-		avgPullReqContribs += float64(totalPullReqContribs) / float64(len(gitHubIdList))
-		avgReposWithContribPullReqs += float64(totalReposWithContribPullReqs) / float64(len(gitHubIdList))
-		avgPullReqReviewContribs += float64(totalPullReqReviewContribs) / float64(len(gitHubIdList))
-		avgReposWithContribPullReqReviews += float64(totalReposWithContribPullReqReviews) / float64(len(gitHubIdList))
 	}
 
+	// then gather the values for each metric from the users that make up our baseline
+	// (skipping any users that were excluded from it or that weren't on the team during
+	// the time window)
+	baselineValues := map[string][]float64{}
+	for gitHubId, userValues := range valuesByUser {
+		if !utils.SliceContains(baselineIds, gitHubId) || utils.SliceContains(excludedIds, gitHubId) {
+			continue
+		}
+		for key, val := range userValues {
+			baselineValues[key] = append(baselineValues[key], val)
+		}
+	}
 	// and add some summary statistics to the output map
 	for _, gitHubId := range userIdList {
 		userMap := contribByUserSummary[gitHubId].(map[string]interface{})
-		if avgPullReqContribs != 0 {
-			userMap["teamPcntPullReqContribs"] = math.Round(((float64(userMap["pullReqContribs"].(int)))/avgPullReqContribs)*10000) / 100
-		}
-		if avgReposWithContribPullReqs != 0 {
-			userMap["teamPcntReposWithContribPullReqs"] = math.Round(((float64(userMap["reposWithPullReqContribs"].(int)))/avgReposWithContribPullReqs)*10000) / 100
-		}
-		if avgPullReqReviewContribs != 0 {
-			userMap["teamPcntPullReqReviewContribs"] = math.Round(((float64(userMap["pullReqReviewContribs"].(int)))/avgPullReqReviewContribs)*10000) / 100
+		userValues, ok := valuesByUser[gitHubId]
+		if !ok {
+			continue
 		}
-		if avgReposWithContribPullReqReviews != 0 {
-			userMap["teamPcntReposWithContribPullReqReviews"] = math.Round(((float64(userMap["reposWithPullReqReviewsContribs"].(int)))/avgReposWithContribPullReqReviews)*10000) / 100
+		for _, metric := range contribSummaryMetrics {
+			values := baselineValues[metric.key]
+			mean, standardDeviation := utils.GetMeanAndStandardDeviation(values)
+			center := mean
+			if baselineType == "median" {
+				center = utils.GetMedian(values)
+			}
+			if center != 0 {
+				userMap[metric.teamPcntKey] = math.Round((userValues[metric.key]/center)*10000) / 100
+			}
+			// if requested, add the percentile rank and z-score for this user within the team
+			if viper.GetBool("rankStats") && len(values) > 0 {
+				metricSuffix := strings.TrimPrefix(metric.teamPcntKey, "teamPcnt")
+				userMap["teamRank"+metricSuffix] = math.Round(utils.GetPercentileRank(values, userValues[metric.key])*100) / 100
+				if standardDeviation != 0 {
+					userMap["teamZScore"+metricSuffix] = math.Round((userValues[metric.key]-mean)/standardDeviation*100) / 100
+				}
+			}
 		}
 	}
 
@@ -23,7 +23,7 @@ var (
 the input users against any of the repositories in the named set of GitHub
 organizations.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			utils.DumpMapAsJSON(contribs())
+			utils.DumpMapAsJSON(utils.GetResultsByTeam(contribs))
 		},
 	}
 )
@@ -42,19 +42,21 @@ func init() {
 
 // define the struct that we'll use to determine the total contributions from
 // each of the input usernames to each of the input organizations
+type ContributionTotals struct {
+	TotalIssueContributions                            int
+	TotalRepositoriesWithContributedIssues             int
+	TotalCommitContributions                           int
+	TotalRepositoriesWithContributedCommits            int
+	TotalPullRequestContributions                      int
+	TotalRepositoriesWithContributedPullRequests       int
+	TotalPullRequestReviewContributions                int
+	TotalRepositoriesWithContributedPullRequestReviews int
+}
+
 var ContribQuery struct {
 	User struct {
 		Login                   string
-		ContributionsCollection struct {
-			TotalIssueContributions                            int
-			TotalRepositoriesWithContributedIssues             int
-			TotalCommitContributions                           int
-			TotalRepositoriesWithContributedCommits            int
-			TotalPullRequestContributions                      int
-			TotalRepositoriesWithContributedPullRequests       int
-			TotalPullRequestReviewContributions                int
-			TotalRepositoriesWithContributedPullRequestReviews int
-		} `graphql:"contributionsCollection(from: $from, to: $to, organizationID: $organizationID)"`
+		ContributionsCollection ContributionTotals `graphql:"contributionsCollection(from: $from, to: $to, organizationID: $organizationID)"`
 	} `graphql:"user(login: $login)"`
 }
 
@@ -116,76 +118,80 @@ func contribs() map[string]interface{} {
 	contribsByRepo := map[string]interface{}{}
 	// loop over the list of GitHub IDs
 	for _, gitHubId := range gitHubIdList {
-		// set the login value for this query to the current user's GitHub ID
-		vars["login"] = githubv4.String(gitHubId)
 		// and initialize a map to that will be used to hold the details for
 		// all of the contributions made by this user
 		userCommitContribs := []map[string]interface{}{}
-		// and loop over the list of Org IDs
-		for _, orgId := range orgIdList {
-			// set the "organizationID" field and (re)set the "after" field its
-			// initial value in the "vars" map
-			vars["organizationID"] = orgId
-			// define the variable used to track the cursor values as we go
-			lastCursor := githubv4.String("")
-			// then make requests for the contributions made by this user to this
-			// organization (and continue doing so until we reach the end of the
-			// list of contributions made by this user to this organization in the
-			// specified time period)
-			for {
-				// set the "after" field to our current "lastCursor" value
-				vars["after"] = lastCursor
-				// run our query, returning the results in the CommitContributionsMadeQuery struct
-				err := client.Query(context.Background(), &contributionsMadeQuery, vars)
-				if err != nil {
-					// Handle error.
-					fmt.Fprintln(os.Stderr, err)
-					os.Exit(1)
-				}
-				// grab out the list of edges from the pull request contributions
-				// made and loop over them
-				contribsByRepository := contributionsMadeQuery.User.ContributionsCollection.CommitContributionsByRepository
-				if len(contribsByRepository) == 0 {
-					break
-				}
-				// define a flag we can use to break out of the loop when we reach the end of the list of contributions
-				endOfContributions := false
-				for _, contribByRepository := range contribsByRepository {
-					edges := contribByRepository.Contributions.Edges
-					if len(edges) == 0 {
-						endOfContributions = true
+		// loop over all of the GitHub logins for this user (for users with more than
+		// one GitHub account), gathering the results for all of them under this user
+		for _, login := range utils.GetUserLogins(gitHubId) {
+			// set the login value for this query to the current login
+			vars["login"] = githubv4.String(login)
+			// and loop over the list of Org IDs
+			for _, orgId := range orgIdList {
+				// set the "organizationID" field and (re)set the "after" field its
+				// initial value in the "vars" map
+				vars["organizationID"] = orgId
+				// define the variable used to track the cursor values as we go
+				lastCursor := githubv4.String("")
+				// then make requests for the contributions made by this user to this
+				// organization (and continue doing so until we reach the end of the
+				// list of contributions made by this user to this organization in the
+				// specified time period)
+				for {
+					// set the "after" field to our current "lastCursor" value
+					vars["after"] = lastCursor
+					// run our query, returning the results in the CommitContributionsMadeQuery struct
+					err := client.Query(context.Background(), &contributionsMadeQuery, vars)
+					if err != nil {
+						// Handle error.
+						fmt.Fprintln(os.Stderr, err)
+						os.Exit(1)
+					}
+					// grab out the list of edges from the pull request contributions
+					// made and loop over them
+					contribsByRepository := contributionsMadeQuery.User.ContributionsCollection.CommitContributionsByRepository
+					if len(contribsByRepository) == 0 {
 						break
 					}
-					for _, edge := range edges {
-						// add the details for this edge to the list of commit contributions
-						// made by to the appropriate repository
-						if _, ok := contribsByRepo[edge.Node.Repository.Url]; !ok {
-							// if here, then we haven't seen this repository yet so create a new entry for it
-							contribsByRepo[edge.Node.Repository.Url] = map[string]interface{}{
-								"repositoryName":     edge.Node.Repository.Name,
-								"totalContributions": edge.Node.CommitCount,
-							}
-						} else {
-							// else just increment the number of contributions made to this repository
-							repoContribsMap := contribsByRepo[edge.Node.Repository.Url].(map[string]interface{})
-							if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
-								repoContribsMap["totalContributions"] = currentCount + edge.Node.CommitCount
+					// define a flag we can use to break out of the loop when we reach the end of the list of contributions
+					endOfContributions := false
+					for _, contribByRepository := range contribsByRepository {
+						edges := contribByRepository.Contributions.Edges
+						if len(edges) == 0 {
+							endOfContributions = true
+							break
+						}
+						for _, edge := range edges {
+							// add the details for this edge to the list of commit contributions
+							// made by to the appropriate repository
+							if _, ok := contribsByRepo[edge.Node.Repository.Url]; !ok {
+								// if here, then we haven't seen this repository yet so create a new entry for it
+								contribsByRepo[edge.Node.Repository.Url] = map[string]interface{}{
+									"repositoryName":     edge.Node.Repository.Name,
+									"totalContributions": edge.Node.CommitCount,
+								}
+							} else {
+								// else just increment the number of contributions made to this repository
+								repoContribsMap := contribsByRepo[edge.Node.Repository.Url].(map[string]interface{})
+								if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
+									repoContribsMap["totalContributions"] = currentCount + edge.Node.CommitCount
+								}
 							}
+							// and add the details for this edge to the list of commit contributions
+							// made by this user (these edges are organized by date/repository pairs)
+							userCommitContribs = append(userCommitContribs, map[string]interface{}{
+								"repositoryName":   edge.Node.Repository.Name,
+								"numContributions": edge.Node.CommitCount,
+								"contributedAt":    utils.InTimeZone(edge.Node.OccurredAt.Time),
+							})
+							// and save the cursor value for this edge for use later on
+							lastCursor = edge.Cursor
 						}
-						// and add the details for this edge to the list of commit contributions
-						// made by this user (these edges are organized by date/repository pairs)
-						userCommitContribs = append(userCommitContribs, map[string]interface{}{
-							"repositoryName":   edge.Node.Repository.Name,
-							"numContributions": edge.Node.CommitCount,
-							"contributedAt":    edge.Node.OccurredAt,
-						})
-						// and save the cursor value for this edge for use later on
-						lastCursor = edge.Cursor
 					}
-				}
-				// if we've reached the end of the list of contributions, break out of the loop
-				if endOfContributions {
-					break
+					// if we've reached the end of the list of contributions, break out of the loop
+					if endOfContributions {
+						break
+					}
 				}
 			}
 		}
@@ -13,11 +13,12 @@ import (
 var (
 	contribsByTypeCmd = &cobra.Command{
 		Use:   "contribsByType",
-		Short: "Generates a list of PRs and PR reviews made",
-		Long: `Constructs a list of PRs and PR reviews made by each of the input users
-against any of the repositories in the named set of GitHub organizations.`,
+		Short: "Generates a list of PRs, PR reviews, issues, and issue comments made",
+		Long: `Constructs a list of PRs, PR reviews, issues, and issue comments made by each
+of the input users against any of the repositories in the named set of GitHub
+organizations.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			contribsByType()
+			utils.DumpMapAsJSON(utils.GetResultsByTeam(contribsByType))
 		},
 	}
 )
@@ -35,11 +36,12 @@ func init() {
 }
 
 /*
- * define the function that is used to print (as a JSON string) the information
+ * define the function that is used to gather the information
  * for all of the pull request contributions (both pull requests, and pull request reviews)
- * made by the named user(s) against repositories under the named org(s)
+ * and issue contributions (both issues and issue comments) made by the named user(s)
+ * against repositories under the named org(s)
  */
-func contribsByType() {
+func contribsByType() map[string]interface{} {
 	// initialize the map used to track the contributions (grouped by type of contribution)
 	contribsByUser := map[string]interface{}{}
 	// first, fetch the list of PRs made by the named user(s) against repositories
@@ -48,6 +50,10 @@ func contribsByType() {
 	// then append onto that the list of PR reviews made by the named user(s) against
 	// repositories under the named org(s)
 	contribsByUser["pullRequestReviews"] = prReviews()
-	// and dump out the results
-	utils.DumpMapAsJSON(contribsByUser)
+	// followed by the list of issues opened and the list of comments made on issues
+	// by the named user(s) in repositories under the named org(s)
+	contribsByUser["issues"] = issueList()
+	contribsByUser["issueComments"] = issueCommentsList()
+	// and return the results
+	return contribsByUser
 }
@@ -0,0 +1,310 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package user
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// heatmapCmd represents the 'heatmap' command
+var (
+	heatmapFormat  string
+	heatmapSources string
+	heatmapCmd     = &cobra.Command{
+		Use:   "heatmap",
+		Short: "Generates a weekday by hour heatmap of the contributions made",
+		Long: `Constructs a heatmap showing when the contributions made by each of the input
+users (and by all of those users, taken together) in the named set of GitHub
+organizations occurred, bucketing the timestamps for those contributions into
+a grid with one row for each day of the week and one column for each hour of
+the day (in the configured time zone); by default only pull requests and
+reviews are included, since GitHub only reports the day on which commits were
+made (so the hour used for commits, if they are requested using the --sources
+flag, is not meaningful). The output can be formatted as JSON, as CSV, or
+rendered as an ASCII or SVG image.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			sources := getHeatmapSources()
+			switch strings.ToLower(viper.GetString("heatmapFormat")) {
+			case "", "json":
+				utils.DumpMapAsJSON(utils.GetResultsByTeam(func() map[string]interface{} {
+					return heatmap(sources)
+				}))
+			case "csv":
+				utils.CheckSingleTeamFormat("csv")
+				utils.DumpText(heatmapAsCSV(heatmap(sources)))
+			case "ascii":
+				utils.CheckSingleTeamFormat("ascii")
+				utils.DumpText(heatmapAsASCII(heatmap(sources)))
+			case "svg":
+				utils.CheckSingleTeamFormat("svg")
+				utils.DumpText(heatmapAsSVG(heatmap(sources)))
+			default:
+				fmt.Fprintf(os.Stderr, "ERROR: unrecognized output format '%s'; expected 'json', 'csv', 'ascii', or 'svg'\n", viper.GetString("heatmapFormat"))
+				os.Exit(-9)
+			}
+		},
+	}
+)
+
+func init() {
+	cmd.UserCmd.AddCommand(heatmapCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	heatmapCmd.Flags().StringVar(&heatmapFormat, "format", "json", "output format for the heatmap (json, csv, ascii, or svg)")
+	heatmapCmd.Flags().StringVar(&heatmapSources, "sources", "pullRequests,reviews", "comma-separated list of contribution types to include (pullRequests, reviews, or commits; commits are day-granular)")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("heatmapFormat", heatmapCmd.Flags().Lookup("format"))
+	viper.BindPFlag("heatmapSources", heatmapCmd.Flags().Lookup("sources"))
+}
+
+/*
+ * define the sources of the timestamps used to construct the heatmap, along with the
+ * function used to gather the contributions from each source and the key used to find
+ * the timestamp for each of those contributions; note that GitHub only reports the day
+ * on which commits were made, so the hour used for commits is less meaningful than the
+ * hour used for the other sources (which is why commits aren't included by default)
+ */
+type heatmapSource struct {
+	timestampKey string
+	getResults   func() map[string]interface{}
+}
+
+var heatmapSourceMap = map[string]heatmapSource{
+	"commits":      {"contributedAt", contribs},
+	"pullRequests": {"createdAt", prList},
+	"reviews":      {"occurredAt", prReviews},
+}
+
+// the names of the rows in the heatmap (our weeks start on Monday)
+var heatmapDayNames = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+/*
+ * a utility function that returns the (validated) list of sources to include
+ * in the heatmap
+ */
+func getHeatmapSources() []string {
+	sources := []string{}
+	for _, source := range strings.Split(viper.GetString("heatmapSources"), ",") {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			continue
+		}
+		if _, ok := heatmapSourceMap[source]; !ok {
+			fmt.Fprintf(os.Stderr, "ERROR: unrecognized source '%s'; expected 'commits', 'pullRequests', or 'reviews'\n", source)
+			os.Exit(-9)
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: at least one source must be included in the heatmap\n")
+		os.Exit(-9)
+	}
+	return sources
+}
+
+/*
+ * define the function that is used to construct the heatmap for the contributions
+ * made by the named user(s) against repositories under the named org(s)
+ */
+func heatmap(sources []string) map[string]interface{} {
+	// define the start and end time of our query window
+	startDateTime, endDateTime := utils.GetQueryTimeWindow()
+	gitHubIdList := utils.GetUserIdList()
+	timeZone := utils.GetTimeZone()
+	// initialize the grids for each user and for all users
+	byUser := map[string][][]int{}
+	for _, gitHubId := range gitHubIdList {
+		byUser[gitHubId] = newHeatmapGrid()
+	}
+	allUsers := newHeatmapGrid()
+	// then gather the contributions from each source and bucket their timestamps
+	for _, source := range sources {
+		results := heatmapSourceMap[source].getResults()
+		for _, gitHubId := range gitHubIdList {
+			for _, contrib := range getUserContribList(results, gitHubId) {
+				timestamp, ok := contrib[heatmapSourceMap[source].timestampKey].(time.Time)
+				if !ok || timestamp.IsZero() {
+					continue
+				}
+				timestamp = timestamp.In(timeZone)
+				// convert the weekday so that Monday is the first row
+				day := (int(timestamp.Weekday()) + 6) % 7
+				count := 1
+				if numContribs, ok := contrib["numContributions"].(int); ok {
+					count = numContribs
+				}
+				byUser[gitHubId][day][timestamp.Hour()] += count
+				allUsers[day][timestamp.Hour()] += count
+			}
+		}
+	}
+	byUserMap := map[string]interface{}{}
+	for gitHubId, grid := range byUser {
+		byUserMap[gitHubId] = grid
+	}
+	// and return the results
+	return map[string]interface{}{"title": "Contribution Heatmap",
+		"start": startDateTime.Format(cmd.ISO8601_FormatStr), "end": endDateTime.Format(cmd.ISO8601_FormatStr),
+		"timeZone": timeZone.String(), "sources": sources, "days": heatmapDayNames,
+		"members": gitHubIdList, "byUser": byUserMap, "allUsers": allUsers}
+}
+
+/*
+ * a utility function that returns an empty (7x24) heatmap grid
+ */
+func newHeatmapGrid() [][]int {
+	grid := make([][]int, len(heatmapDayNames))
+	for day := range grid {
+		grid[day] = make([]int, 24)
+	}
+	return grid
+}
+
+/*
+ * a utility function that returns the largest count in a heatmap grid
+ */
+func getHeatmapMax(grid [][]int) int {
+	maxCount := 0
+	for _, row := range grid {
+		for _, count := range row {
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+	}
+	return maxCount
+}
+
+/*
+ * a utility function that returns the list of named grids (one for all users, followed
+ * by one for each user) from the results returned by the heatmap function (above)
+ */
+func getHeatmapGrids(results map[string]interface{}) ([]string, [][][]int) {
+	names := []string{"allUsers"}
+	grids := [][][]int{results["allUsers"].([][]int)}
+	for _, gitHubId := range results["members"].([]string) {
+		names = append(names, gitHubId)
+		grids = append(grids, results["byUser"].(map[string]interface{})[gitHubId].([][]int))
+	}
+	return names, grids
+}
+
+/*
+ * a function that formats the results returned by the heatmap function (above) as CSV,
+ * with one row for each user and day of the week and one column for each hour of the day
+ */
+func heatmapAsCSV(results map[string]interface{}) string {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := []string{"user", "day"}
+	for hour := 0; hour < 24; hour++ {
+		header = append(header, fmt.Sprintf("%02d", hour))
+	}
+	writer.Write(header)
+	names, grids := getHeatmapGrids(results)
+	for idx, grid := range grids {
+		for day, row := range grid {
+			record := []string{names[idx], heatmapDayNames[day]}
+			for _, count := range row {
+				record = append(record, strconv.Itoa(count))
+			}
+			writer.Write(record)
+		}
+	}
+	writer.Flush()
+	return buf.String()
+}
+
+// the characters used to shade the cells in the ASCII rendering (from lightest to darkest)
+const heatmapShades = " .:-=+*#%@"
+
+/*
+ * a function that renders the results returned by the heatmap function (above) as
+ * ASCII art, with one block for each user (and one for all users) where each cell is
+ * shaded relative to the largest count in that block
+ */
+func heatmapAsASCII(results map[string]interface{}) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s (%s to %s, %s)\n", results["title"], results["start"], results["end"], results["timeZone"])
+	names, grids := getHeatmapGrids(results)
+	for idx, grid := range grids {
+		maxCount := getHeatmapMax(grid)
+		fmt.Fprintf(&buf, "\n%s (max %d)\n    ", names[idx], maxCount)
+		for hour := 0; hour < 24; hour += 3 {
+			fmt.Fprintf(&buf, "%-3d", hour)
+		}
+		buf.WriteString("\n")
+		for day, row := range grid {
+			fmt.Fprintf(&buf, "%s ", heatmapDayNames[day])
+			for _, count := range row {
+				shade := 0
+				if maxCount > 0 && count > 0 {
+					// any non-zero count gets at least the lightest visible shade
+					shade = 1 + (count*(len(heatmapShades)-2))/maxCount
+				}
+				buf.WriteByte(heatmapShades[shade])
+			}
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+/*
+ * a function that renders the results returned by the heatmap function (above) as an
+ * SVG image, with one grid for each user (and one for all users) where the opacity of
+ * each cell is relative to the largest count in that grid
+ */
+func heatmapAsSVG(results map[string]interface{}) string {
+	const cellSize, labelWidth, titleHeight, gridGap = 16, 40, 20, 30
+	names, grids := getHeatmapGrids(results)
+	gridHeight := titleHeight + cellSize*(len(heatmapDayNames)+1)
+	width := labelWidth + cellSize*24 + 10
+	height := len(grids)*(gridHeight+gridGap) + titleHeight
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"sans-serif\" font-size=\"10\">\n", width, height)
+	fmt.Fprintf(&buf, "  <text x=\"0\" y=\"12\" font-size=\"12\">%s (%s to %s, %s)</text>\n", results["title"], results["start"], results["end"], results["timeZone"])
+	for idx, grid := range grids {
+		maxCount := getHeatmapMax(grid)
+		top := titleHeight + idx*(gridHeight+gridGap)
+		fmt.Fprintf(&buf, "  <g transform=\"translate(0,%d)\">\n", top)
+		fmt.Fprintf(&buf, "    <text x=\"0\" y=\"%d\" font-size=\"12\">%s (max %d)</text>\n", titleHeight-6, names[idx], maxCount)
+		for hour := 0; hour < 24; hour += 3 {
+			fmt.Fprintf(&buf, "    <text x=\"%d\" y=\"%d\">%d</text>\n", labelWidth+hour*cellSize, titleHeight+cellSize-4, hour)
+		}
+		for day, row := range grid {
+			y := titleHeight + cellSize*(day+1)
+			fmt.Fprintf(&buf, "    <text x=\"0\" y=\"%d\">%s</text>\n", y+cellSize-4, heatmapDayNames[day])
+			for hour, count := range row {
+				opacity := 0.0
+				if maxCount > 0 {
+					opacity = float64(count) / float64(maxCount)
+				}
+				fmt.Fprintf(&buf, "    <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"#216e39\" fill-opacity=\"%.2f\" stroke=\"#eeeeee\"><title>%s %02d:00 - %d</title></rect>\n",
+					labelWidth+hour*cellSize, y, cellSize, cellSize, opacity, heatmapDayNames[day], hour, count)
+			}
+		}
+		buf.WriteString("  </g>\n")
+	}
+	buf.WriteString("</svg>\n")
+	return buf.String()
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package user
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// issueCommentsCmd represents the 'issueComments' command
+var issueCommentsCmd = &cobra.Command{
+	Use:   "issueComments",
+	Short: "Generates a list the comments made on issues",
+	Long: `Constructs a list of all of the comments that each of the input users made
+on issues in any of the repositories in the named set of GitHub organizations
+(including the title, url, author, and repository name of the issue that was
+commented on); comments on pull requests are not included.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		utils.DumpMapAsJSON(utils.GetResultsByTeam(issueCommentsList))
+	},
+}
+
+func init() {
+	cmd.UserCmd.AddCommand(issueCommentsCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+}
+
+/*
+ * define a struct that can be used to put together a list of all of the issue
+ * comments made by a given user; unlike the contributions collections used
+ * elsewhere, this connection can't be restricted to a given organization or
+ * time window, so the comments are returned (most recently updated first) and
+ * filtered as we go
+ */
+type IssueCommentEdges struct {
+	Cursor githubv4.String
+	Node   struct {
+		CreatedAt githubv4.DateTime
+		UpdatedAt githubv4.DateTime
+		Url       string
+		Issue     struct {
+			Author struct {
+				Login string
+			}
+			Repository struct {
+				Name  string
+				Url   string
+				Owner struct {
+					Login string
+				}
+			}
+			Title string
+			Url   string
+		}
+		PullRequest struct {
+			Url string
+		}
+	}
+}
+
+var issueCommentsMadeQuery struct {
+	User struct {
+		Login         string
+		IssueComments struct {
+			Edges    []IssueCommentEdges
+			PageInfo cmd.PageInfo
+		} `graphql:"issueComments(first: $first, after: $after, orderBy: {field: UPDATED_AT, direction: DESC})"`
+	} `graphql:"user(login: $login)"`
+}
+
+/*
+ * a utility function that returns true if the input login matches one of the
+ * organization names in the input list (ignoring case)
+ */
+func isOrgInList(login string, orgNameList []string) bool {
+	for _, orgName := range orgNameList {
+		if strings.EqualFold(login, orgName) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * define the function that is used to fetch the comments made by the named
+ * user(s) on issues in repositories under the named org(s)
+ */
+func issueCommentsList() map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	// and then get the list of organization names that we want to include
+	orgNameList := utils.GetOrgNameList()
+	// define the start and end time of our query window
+	startDateTime, endDateTime := utils.GetQueryTimeWindow()
+	// define a list that we'll use later on (to loop over the team members)
+	gitHubIdList := utils.GetUserIdList()
+	// initialize the vars map that we'll use when making our query for issue comments
+	vars := map[string]interface{}{
+		"first": githubv4.Int(100),
+	}
+	commentsByUser := map[string]interface{}{}
+	commentsByRepo := map[string]interface{}{}
+	// loop over the list of GitHub IDs
+	for _, gitHubId := range gitHubIdList {
+		// and initialize a map to that will be used to hold the details for
+		// all of the issue comments made by this user
+		userComments := []map[string]interface{}{}
+		// loop over all of the GitHub logins for this user (for users with more than
+		// one GitHub account), gathering the results for all of them under this user
+		for _, login := range utils.GetUserLogins(gitHubId) {
+			// set the login value for this query to the current login
+			vars["login"] = githubv4.String(login)
+			// define the variable used to track the cursor values as we go
+			vars["after"] = githubv4.String("")
+			for {
+				// run our query, returning the results in the issueCommentsMadeQuery struct
+				err := client.Query(context.Background(), &issueCommentsMadeQuery, vars)
+				if err != nil {
+					// Handle error.
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				edges := issueCommentsMadeQuery.User.IssueComments.Edges
+				fmt.Fprintf(os.Stderr, ".")
+				reachedStart := false
+				for _, edge := range edges {
+					comment := edge.Node
+					// the comments are sorted by the time they were last updated (newest first),
+					// so once we find one that was last updated before the start of our time
+					// window, none of the remaining comments could have been made in it
+					if comment.UpdatedAt.Before(startDateTime.Time) {
+						reachedStart = true
+						break
+					}
+					// skip comments made outside of our time window, comments made on pull
+					// requests, and comments made in repositories outside of the named org(s)
+					if comment.CreatedAt.Before(startDateTime.Time) || !comment.CreatedAt.Before(endDateTime.Time) ||
+						comment.PullRequest.Url != "" || !isOrgInList(comment.Issue.Repository.Owner.Login, orgNameList) {
+						continue
+					}
+					// add this comment to the count of comments made in the appropriate repository
+					repository := comment.Issue.Repository
+					if _, ok := commentsByRepo[repository.Url]; !ok {
+						// if here, then we haven't seen this repository yet so create a new entry for it
+						commentsByRepo[repository.Url] = map[string]interface{}{
+							"repositoryName":     repository.Name,
+							"totalContributions": 1,
+						}
+					} else {
+						// else just increment the number of contributions made to this repository
+						repoContribsMap := commentsByRepo[repository.Url].(map[string]interface{})
+						if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
+							repoContribsMap["totalContributions"] = currentCount + 1
+						}
+					}
+					// add the details for this comment to the list of comments made by this user
+					userComments = append(userComments, map[string]interface{}{
+						"createdAt":      utils.InTimeZone(comment.CreatedAt.Time),
+						"issueAuthor":    comment.Issue.Author.Login,
+						"issueTitle":     comment.Issue.Title,
+						"issueUrl":       comment.Issue.Url,
+						"ownIssue":       utils.GetPersonId(comment.Issue.Author.Login) == gitHubId,
+						"repositoryName": repository.Name,
+						"url":            comment.Url,
+					})
+				}
+				// if we've reached the start of our time window or the end of the list of
+				// comments, then break out of the loop
+				pageInfo := issueCommentsMadeQuery.User.IssueComments.PageInfo
+				if reachedStart || !pageInfo.HasNextPage {
+					break
+				}
+				vars["after"] = pageInfo.EndCursor
+			}
+		}
+		fmt.Fprintf(os.Stderr, "\nFound %d issue comments for user %s\n", len(userComments), gitHubId)
+		// add the issue comments for this user to the complete list of issue comments by user
+		if _, ok := commentsByUser["ByUser"]; ok {
+			if val, ok := commentsByUser["ByUser"].([]map[string]interface{}); ok {
+				commentsByUser["ByUser"] = append(val, map[string]interface{}{
+					gitHubId: userComments,
+				})
+			}
+		} else {
+			commentsByUser["ByUser"] = append([]map[string]interface{}{}, map[string]interface{}{
+				gitHubId: userComments,
+			})
+		}
+	}
+	// finally add an "AllUsers" entry to the list of contributions made by all users to each repository
+	commentsByUser["AllUsers"] = commentsByRepo
+	return commentsByUser
+}
@@ -0,0 +1,189 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package user
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// issueListCmd represents the 'issueList' command
+var issueListCmd = &cobra.Command{
+	Use:   "issueList",
+	Short: "Generates a list the issues opened",
+	Long: `Constructs a list of all of the issues that each of the input users opened
+in any of the repositories in the named set of GitHub organizations (including
+the title, status, url, and repository name) for each issue opened by that user.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		utils.DumpMapAsJSON(utils.GetResultsByTeam(issueList))
+	},
+}
+
+func init() {
+	cmd.UserCmd.AddCommand(issueListCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+}
+
+/*
+ * define a struct that can be used put together a list of all of the issues
+ * opened by a given user in any repository in a given organization
+ */
+type IssueEdges struct {
+	Cursor githubv4.String
+	Node   struct {
+		Issue struct {
+			Author struct {
+				Login string
+			}
+			Closed     bool
+			ClosedAt   githubv4.DateTime
+			CreatedAt  githubv4.DateTime
+			Repository struct {
+				Name string
+				Url  string
+			}
+			Comments struct {
+				TotalCount int
+			}
+			Title string
+			Url   string
+		}
+	}
+}
+
+var issuesOpenedQuery struct {
+	User struct {
+		Login                   string
+		ContributionsCollection struct {
+			IssueContributions struct {
+				Edges []IssueEdges
+			} `graphql:"issueContributions(first: $first, after: $after)"`
+		} `graphql:"contributionsCollection(from: $from, to: $to, organizationID: $organizationID)"`
+	} `graphql:"user(login: $login)"`
+}
+
+/*
+ * define the function that is used to fetch the GitHub issue information
+ * for the issues opened by the named user(s) in repositories under the
+ * named org(s)
+ */
+func issueList() map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	// and then get the list of organization IDs that we want to query
+	orgIdList := utils.GetOrgIdList(client)
+	// define the start and end time of our query window
+	startDateTime, endDateTime := utils.GetQueryTimeWindow()
+	// define a list that we'll use later on (to loop over the team members)
+	gitHubIdList := utils.GetUserIdList()
+	// initialize the vars map that we'll use when making our query for issue contributions
+	vars := map[string]interface{}{
+		"from":  startDateTime,
+		"to":    endDateTime,
+		"first": githubv4.Int(100),
+	}
+	issuesByUser := map[string]interface{}{}
+	issuesByRepo := map[string]interface{}{}
+	// loop over the list of GitHub IDs
+	for _, gitHubId := range gitHubIdList {
+		// and initialize a map to that will be used to hold the details for
+		// all of the issues opened by this user
+		userIssues := []map[string]interface{}{}
+		// loop over all of the GitHub logins for this user (for users with more than
+		// one GitHub account), gathering the results for all of them under this user
+		for _, login := range utils.GetUserLogins(gitHubId) {
+			// set the login value for this query to the current login
+			vars["login"] = githubv4.String(login)
+			// and loop over the list of Org IDs
+			for _, orgId := range orgIdList {
+				// set the "organizationID" field and (re)set the "after" field its
+				// initial value in the "vars" map
+				vars["organizationID"] = orgId
+				// define the variable used to track the cursor values as we go
+				lastCursor := githubv4.String("")
+				// then make requests for the issues opened by this user in this organization
+				// (and continue doing so until we reach the end of the list of issues opened
+				// by this user in this organization in the specified time period)
+				for {
+					// set the "after" field to our current "lastCursor" value
+					vars["after"] = lastCursor
+					// run our query, returning the results in the issuesOpenedQuery struct
+					err := client.Query(context.Background(), &issuesOpenedQuery, vars)
+					if err != nil {
+						// Handle error.
+						fmt.Fprintln(os.Stderr, err)
+						os.Exit(1)
+					}
+					// grab out the list of edges from the issue contributions made and loop over them
+					edges := issuesOpenedQuery.User.ContributionsCollection.IssueContributions.Edges
+					// if nothing was returned, then we've found all of the contributions
+					// from this user to this organization so break out of the loop
+					if len(edges) == 0 {
+						break
+					}
+					fmt.Fprintf(os.Stderr, "Found %d issue contributions for user %s to org %s\n", len(edges), login, orgId)
+					for _, edge := range edges {
+						// save some typing later by grabbing the issue associated with this edge
+						issue := edge.Node.Issue
+						// add this issue to the count of issues opened in the appropriate repository
+						if _, ok := issuesByRepo[issue.Repository.Url]; !ok {
+							// if here, then we haven't seen this repository yet so create a new entry for it
+							issuesByRepo[issue.Repository.Url] = map[string]interface{}{
+								"repositoryName":     issue.Repository.Name,
+								"totalContributions": 1,
+							}
+						} else {
+							// else just increment the number of contributions made to this repository
+							repoContribsMap := issuesByRepo[issue.Repository.Url].(map[string]interface{})
+							if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
+								repoContribsMap["totalContributions"] = currentCount + 1
+							}
+						}
+						// add the details for this edge to the list of issues opened by this user
+						userIssues = append(userIssues, map[string]interface{}{
+							"author":         issue.Author.Login,
+							"closed":         issue.Closed,
+							"closedAt":       utils.InTimeZone(issue.ClosedAt.Time),
+							"comments":       issue.Comments.TotalCount,
+							"createdAt":      utils.InTimeZone(issue.CreatedAt.Time),
+							"repositoryName": issue.Repository.Name,
+							"title":          issue.Title,
+							"url":            issue.Url,
+						})
+						// and save the cursor value for this edge for use later on
+						lastCursor = edge.Cursor
+					}
+				}
+			}
+		}
+		// add the issues for this user to the complete list of issues by user
+		if _, ok := issuesByUser["ByUser"]; ok {
+			if val, ok := issuesByUser["ByUser"].([]map[string]interface{}); ok {
+				issuesByUser["ByUser"] = append(val, map[string]interface{}{
+					gitHubId: userIssues,
+				})
+			}
+		} else {
+			issuesByUser["ByUser"] = append([]map[string]interface{}{}, map[string]interface{}{
+				gitHubId: userIssues,
+			})
+		}
+	}
+	// finally add an "AllUsers" entry to the list of contributions made by all users to each repository
+	issuesByUser["AllUsers"] = issuesByRepo
+	return issuesByUser
+}
@@ -25,7 +25,7 @@ made to any repository to any of the repositories in the named set of GitHub
 organizations (including the title, status, url, and repository name) for each
 pull request submitted by that user.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		utils.DumpMapAsJSON(prList())
+		utils.DumpMapAsJSON(utils.GetResultsByTeam(prList))
 	},
 }
 
@@ -73,6 +73,7 @@ type commitEdges struct {
 type PullRequestEdges struct {
 	Cursor githubv4.String
 	Node   struct {
+		OccurredAt  githubv4.DateTime
 		PullRequest struct {
 			Author struct {
 				Login string
@@ -135,98 +136,102 @@ func prList() map[string]interface{} {
 	prsByRepo := map[string]interface{}{}
 	// loop over the list of GitHub IDs
 	for _, gitHubId := range gitHubIdList {
-		// set the login value for this query to the current user's GitHub ID
-		vars["login"] = githubv4.String(gitHubId)
 		// and initialize a map to that will be used to hold the details for
 		// all of the pull requests made by this user
 		userPullRequests := []map[string]interface{}{}
-		// and loop over the list of Org IDs
-		for _, orgId := range orgIdList {
-			// set the "organizationID" field and (re)set the "after" field its
-			// initial value in the "vars" map
-			vars["organizationID"] = orgId
-			// define the variable used to track the cursor values as we go
-			lastCursor := githubv4.String("")
-			// then make requests for the pull requests made by this user to this
-			// organization (and continue doing so until we reach the end of the
-			// list of pull requests made by this user to this organization in the
-			// specified time period)
-			for {
-				// set the "after" field to our current "lastCursof" value
-				vars["after"] = lastCursor
-				// run our query, returning the results in the PullRequestsMadeQuery struct
-				err := client.Query(context.Background(), &pullRequestsMadeQuery, vars)
-				if err != nil {
-					// Handle error.
-					fmt.Fprintln(os.Stderr, err)
-					os.Exit(1)
-				}
-				// grab out the list of edges from the pull request contributions
-				// made and loop over them
-				edges := pullRequestsMadeQuery.User.ContributionsCollection.PullRequestContributions.Edges
-				// if nothing was returned, then we've found all of the contributions
-				// from this user to this organization so break out of the loop
-				if len(edges) == 0 {
-					break
-				}
-				fmt.Fprintf(os.Stderr, "Found %d pull request contributions for user %s to org %s\n", len(edges), gitHubId, orgId)
-				for _, edge := range edges {
-					// save some typing later by grabbing the pull request associated with this edge
-					pullReq := edge.Node.PullRequest
-					// if the pull rquest was closed as merged, then add the details for this
-					// edge to the list of commit contributions made by to the appropriate
-					// repository
-					if pullReq.Closed && pullReq.Merged {
-						if _, ok := prsByRepo[pullReq.Repository.Url]; !ok {
-							// if here, then we haven't seen this repository yet so create a new entry for it
-							prsByRepo[pullReq.Repository.Url] = map[string]interface{}{
-								"repositoryName":     pullReq.Repository.Name,
-								"totalContributions": 1,
+		// loop over all of the GitHub logins for this user (for users with more than
+		// one GitHub account), gathering the results for all of them under this user
+		for _, login := range utils.GetUserLogins(gitHubId) {
+			// set the login value for this query to the current login
+			vars["login"] = githubv4.String(login)
+			// and loop over the list of Org IDs
+			for _, orgId := range orgIdList {
+				// set the "organizationID" field and (re)set the "after" field its
+				// initial value in the "vars" map
+				vars["organizationID"] = orgId
+				// define the variable used to track the cursor values as we go
+				lastCursor := githubv4.String("")
+				// then make requests for the pull requests made by this user to this
+				// organization (and continue doing so until we reach the end of the
+				// list of pull requests made by this user to this organization in the
+				// specified time period)
+				for {
+					// set the "after" field to our current "lastCursof" value
+					vars["after"] = lastCursor
+					// run our query, returning the results in the PullRequestsMadeQuery struct
+					err := client.Query(context.Background(), &pullRequestsMadeQuery, vars)
+					if err != nil {
+						// Handle error.
+						fmt.Fprintln(os.Stderr, err)
+						os.Exit(1)
+					}
+					// grab out the list of edges from the pull request contributions
+					// made and loop over them
+					edges := pullRequestsMadeQuery.User.ContributionsCollection.PullRequestContributions.Edges
+					// if nothing was returned, then we've found all of the contributions
+					// from this user to this organization so break out of the loop
+					if len(edges) == 0 {
+						break
+					}
+					fmt.Fprintf(os.Stderr, "Found %d pull request contributions for user %s to org %s\n", len(edges), login, orgId)
+					for _, edge := range edges {
+						// save some typing later by grabbing the pull request associated with this edge
+						pullReq := edge.Node.PullRequest
+						// if the pull rquest was closed as merged, then add the details for this
+						// edge to the list of commit contributions made by to the appropriate
+						// repository
+						if pullReq.Closed && pullReq.Merged {
+							if _, ok := prsByRepo[pullReq.Repository.Url]; !ok {
+								// if here, then we haven't seen this repository yet so create a new entry for it
+								prsByRepo[pullReq.Repository.Url] = map[string]interface{}{
+									"repositoryName":     pullReq.Repository.Name,
+									"totalContributions": 1,
+								}
+							} else {
+								// else just increment the number of contributions made to this repository
+								repoContribsMap := prsByRepo[pullReq.Repository.Url].(map[string]interface{})
+								if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
+									repoContribsMap["totalContributions"] = currentCount + 1
+								}
 							}
+						}
+						// determine how long the pull request was open (or has been open if it's still open)
+						// after it was created along with the time since the first commit was made
+						daysOpen := 0.0
+						daysSinceFirstCommit := 0.0
+						firstCommitAt := pullReq.Commits.Edges[0].Node.Commit.CommittedDate.Time
+						if pullReq.Closed && !pullReq.Merged {
+							// pull request was closed but not merged
+							daysOpen = math.Round(pullReq.ClosedAt.Sub(pullReq.CreatedAt.Time).Hours()/24.0*10000) / 10000
+							daysSinceFirstCommit = math.Round(pullReq.ClosedAt.Sub(firstCommitAt).Hours()/24.0*10000) / 10000
+						} else if pullReq.Merged {
+							// pull request was merged
+							daysOpen = math.Round(pullReq.MergedAt.Sub(pullReq.CreatedAt.Time).Hours()/24.0*10000) / 10000
+							daysSinceFirstCommit = math.Round(pullReq.MergedAt.Sub(firstCommitAt).Hours()/24.0*10000) / 10000
 						} else {
-							// else just increment the number of contributions made to this repository
-							repoContribsMap := prsByRepo[pullReq.Repository.Url].(map[string]interface{})
-							if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
-								repoContribsMap["totalContributions"] = currentCount + 1
-							}
+							// pull request is still open today (so used time elapsed since it was created)
+							daysOpen = math.Round(time.Since(pullReq.CreatedAt.Time).Hours()/24.0*10000) / 10000
+							daysSinceFirstCommit = math.Round(time.Since(firstCommitAt).Hours()/24.0*10000) / 10000
 						}
+						// add the details for this edge to the list of pull requests
+						// made by this user
+						userPullRequests = append(userPullRequests, map[string]interface{}{
+							"author":         pullReq.Author.Login,
+							"closed":         pullReq.Closed,
+							"closedAt":       utils.InTimeZone(pullReq.ClosedAt.Time),
+							"createdAt":      utils.InTimeZone(pullReq.CreatedAt.Time),
+							"daysOpen":       daysOpen,
+							"daysWorked":     math.Max(daysOpen, daysSinceFirstCommit),
+							"firstCommitAt":  utils.InTimeZone(firstCommitAt),
+							"merged":         pullReq.Merged,
+							"mergedAt":       utils.InTimeZone(pullReq.MergedAt.Time),
+							"repositoryName": pullReq.Repository.Name,
+							"title":          pullReq.Title,
+							"url":            pullReq.Url,
+						})
+						// and save the cursor value for this edge for use later on
+						lastCursor = edge.Cursor
 					}
-					// determine how long the pull request was open (or has been open if it's still open)
-					// after it was created along with the time since the first commit was made
-					daysOpen := 0.0
-					daysSinceFirstCommit := 0.0
-					firstCommitAt := pullReq.Commits.Edges[0].Node.Commit.CommittedDate.Time
-					if pullReq.Closed && !pullReq.Merged {
-						// pull request was closed but not merged
-						daysOpen = math.Round(pullReq.ClosedAt.Sub(pullReq.CreatedAt.Time).Hours()/24.0*10000) / 10000
-						daysSinceFirstCommit = math.Round(pullReq.ClosedAt.Sub(firstCommitAt).Hours()/24.0*10000) / 10000
-					} else if pullReq.Merged {
-						// pull request was merged
-						daysOpen = math.Round(pullReq.MergedAt.Sub(pullReq.CreatedAt.Time).Hours()/24.0*10000) / 10000
-						daysSinceFirstCommit = math.Round(pullReq.MergedAt.Sub(firstCommitAt).Hours()/24.0*10000) / 10000
-					} else {
-						// pull request is still open today (so used time elapsed since it was created)
-						daysOpen = math.Round(time.Since(pullReq.CreatedAt.Time).Hours()/24.0*10000) / 10000
-						daysSinceFirstCommit = math.Round(time.Since(firstCommitAt).Hours()/24.0*10000) / 10000
-					}
-					// add the details for this edge to the list of pull requests
-					// made by this user
-					userPullRequests = append(userPullRequests, map[string]interface{}{
-						"author":         pullReq.Author.Login,
-						"closed":         pullReq.Closed,
-						"closedAt":       pullReq.ClosedAt,
-						"createdAt":      pullReq.CreatedAt,
-						"daysOpen":       daysOpen,
-						"daysWorked":     math.Max(daysOpen, daysSinceFirstCommit),
-						"firstCommitAt":  firstCommitAt,
-						"merged":         pullReq.Merged,
-						"mergedAt":       pullReq.MergedAt,
-						"repositoryName": pullReq.Repository.Name,
-						"title":          pullReq.Title,
-						"url":            pullReq.Url,
-					})
-					// and save the cursor value for this edge for use later on
-					lastCursor = edge.Cursor
 				}
 			}
 		}
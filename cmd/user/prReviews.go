@@ -23,7 +23,7 @@ users performed in any repository to any of the repositories in the named set
 of GitHub organizations (including the title, status, url, and repository name)
 for each pull request submitted by that user.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		utils.DumpMapAsJSON(prReviews())
+		utils.DumpMapAsJSON(utils.GetResultsByTeam(prReviews))
 	},
 }
 
@@ -80,74 +80,79 @@ func prReviews() map[string]interface{} {
 	prReviewsByRepo := map[string]interface{}{}
 	// loop over the list of GitHub IDs
 	for _, gitHubId := range gitHubIdList {
-		// set the login value for this query to the current user's GitHub ID
-		vars["login"] = githubv4.String(gitHubId)
 		// and initialize a map to that will be used to hold the details for
 		// all of the pull request reviews made by this user
 		userPullRequestReviews := []map[string]interface{}{}
-		// and loop over the list of Org IDs
-		for _, orgId := range orgIdList {
-			// set the "organizationID" field and (re)set the "after" field its
-			// initial value in the "vars" map
-			vars["organizationID"] = orgId
-			// define the variable used to track the cursor values as we go
-			lastCursor := githubv4.String("")
-			// then make requests for the pull request reviews made by this user
-			// to this organization (and continue doing so until we reach the end
-			// of the list of pull request reviews made by this user to this
-			// organization in the specified time period)
-			for {
-				// set the "after" field to our current "lastCursof" value
-				vars["after"] = lastCursor
-				// run our query, returning the results in the PullRequestReviewsPerformedQuery struct
-				err := client.Query(context.Background(), &pullRequestReviewsPerformedQuery, vars)
-				if err != nil {
-					// Handle error.
-					fmt.Fprintln(os.Stderr, err)
-					os.Exit(1)
-				}
-				// grab out the list of edges from the pull request review
-				// contributions made and loop over them
-				edges := pullRequestReviewsPerformedQuery.User.ContributionsCollection.PullRequestReviewContributions.Edges
-				// if nothing was returned, then we've found all of the contributions
-				// from this user to this organization so break out of the loop
-				if len(edges) == 0 {
-					break
-				}
-				fmt.Fprintf(os.Stderr, "Found %d pull request review contributions for user %s to org %s\n", len(edges), gitHubId, orgId)
-				for _, edge := range edges {
-					// save some typing later by grabbing the pull request associated with this edge
-					pullReq := edge.Node.PullRequest
-					// if the pull rquest review is for a pull request that was closed as merged,
-					// then add the details for this edge to the list of commit contributions
-					// made by to the appropriate repository
-					if pullReq.Closed && pullReq.Merged {
-						if _, ok := prReviewsByRepo[pullReq.Repository.Url]; !ok {
-							// if here, then we haven't seen this repository yet so create a new entry for it
-							prReviewsByRepo[pullReq.Repository.Url] = map[string]interface{}{
-								"repositoryName":     pullReq.Repository.Name,
-								"totalContributions": 1,
-							}
-						} else {
-							// else just increment the number of contributions made to this repository
-							repoContribsMap := prReviewsByRepo[pullReq.Repository.Url].(map[string]interface{})
-							if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
-								repoContribsMap["totalContributions"] = currentCount + 1
+		// loop over all of the GitHub logins for this user (for users with more than
+		// one GitHub account), gathering the results for all of them under this user
+		for _, login := range utils.GetUserLogins(gitHubId) {
+			// set the login value for this query to the current login
+			vars["login"] = githubv4.String(login)
+			// and loop over the list of Org IDs
+			for _, orgId := range orgIdList {
+				// set the "organizationID" field and (re)set the "after" field its
+				// initial value in the "vars" map
+				vars["organizationID"] = orgId
+				// define the variable used to track the cursor values as we go
+				lastCursor := githubv4.String("")
+				// then make requests for the pull request reviews made by this user
+				// to this organization (and continue doing so until we reach the end
+				// of the list of pull request reviews made by this user to this
+				// organization in the specified time period)
+				for {
+					// set the "after" field to our current "lastCursof" value
+					vars["after"] = lastCursor
+					// run our query, returning the results in the PullRequestReviewsPerformedQuery struct
+					err := client.Query(context.Background(), &pullRequestReviewsPerformedQuery, vars)
+					if err != nil {
+						// Handle error.
+						fmt.Fprintln(os.Stderr, err)
+						os.Exit(1)
+					}
+					// grab out the list of edges from the pull request review
+					// contributions made and loop over them
+					edges := pullRequestReviewsPerformedQuery.User.ContributionsCollection.PullRequestReviewContributions.Edges
+					// if nothing was returned, then we've found all of the contributions
+					// from this user to this organization so break out of the loop
+					if len(edges) == 0 {
+						break
+					}
+					fmt.Fprintf(os.Stderr, "Found %d pull request review contributions for user %s to org %s\n", len(edges), login, orgId)
+					for _, edge := range edges {
+						// save some typing later by grabbing the pull request associated with this edge
+						pullReq := edge.Node.PullRequest
+						// if the pull rquest review is for a pull request that was closed as merged,
+						// then add the details for this edge to the list of commit contributions
+						// made by to the appropriate repository
+						if pullReq.Closed && pullReq.Merged {
+							if _, ok := prReviewsByRepo[pullReq.Repository.Url]; !ok {
+								// if here, then we haven't seen this repository yet so create a new entry for it
+								prReviewsByRepo[pullReq.Repository.Url] = map[string]interface{}{
+									"repositoryName":     pullReq.Repository.Name,
+									"totalContributions": 1,
+								}
+							} else {
+								// else just increment the number of contributions made to this repository
+								repoContribsMap := prReviewsByRepo[pullReq.Repository.Url].(map[string]interface{})
+								if currentCount, ok := repoContribsMap["totalContributions"].(int); ok {
+									repoContribsMap["totalContributions"] = currentCount + 1
+								}
 							}
 						}
+						// add the details for this edge to the list of pull request
+						// reviews made by this user
+						userPullRequestReviews = append(userPullRequestReviews, map[string]interface{}{
+							"author":         pullReq.Author.Login,
+							"closed":         pullReq.Closed,
+							"merged":         pullReq.Merged,
+							"occurredAt":     utils.InTimeZone(edge.Node.OccurredAt.Time),
+							"repositoryName": pullReq.Repository.Name,
+							"title":          pullReq.Title,
+							"url":            pullReq.Url,
+						})
+						// and save the cursor value for this edge for use later on
+						lastCursor = edge.Cursor
 					}
-					// add the details for this edge to the list of pull request
-					// reviews made by this user
-					userPullRequestReviews = append(userPullRequestReviews, map[string]interface{}{
-						"author":         pullReq.Author.Login,
-						"closed":         pullReq.Closed,
-						"merged":         pullReq.Merged,
-						"repositoryName": pullReq.Repository.Name,
-						"title":          pullReq.Title,
-						"url":            pullReq.Url,
-					})
-					// and save the cursor value for this edge for use later on
-					lastCursor = edge.Cursor
 				}
 			}
 		}
@@ -0,0 +1,262 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package user
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// reviewLoadCmd represents the 'reviewLoad' command
+var (
+	staleAfter    string
+	reviewLoadCmd = &cobra.Command{
+		Use:   "reviewLoad",
+		Short: "Generates a summary of the review workload for each user",
+		Long: `Constructs a summary of the review workload for each of the input users,
+including the number of review requests that are still pending on open PRs
+in the repositories managed by the named team, the number of reviews that
+each user completed in those repositories in the defined time window, and
+the median time from a review being requested to that review being
+submitted; review requests that have been waiting for longer than a
+threshold are also listed (so that they can be reassigned if needed).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			utils.DumpMapAsJSON(utils.GetResultsByTeam(reviewLoad))
+		},
+	}
+)
+
+func init() {
+	cmd.UserCmd.AddCommand(reviewLoadCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	reviewLoadCmd.Flags().StringVarP(&cmd.RepoMappingFile, "repo-mapping-file", "m", "", "name of the repository mapping file to use")
+	reviewLoadCmd.Flags().StringVar(&staleAfter, "stale-after", "2d", "list pending review requests older than this (eg. 1d, 2d, 1w)")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("repoMappingFile", reviewLoadCmd.Flags().Lookup("repo-mapping-file"))
+	viper.BindPFlag("staleAfter", reviewLoadCmd.Flags().Lookup("stale-after"))
+}
+
+/*
+ * define a struct that can be used to query GitHub for the PRs in a given organization
+ * that match a given query, along with the review requests that are still pending for
+ * each of those PRs and the timeline of review requests and reviews for each of them
+ */
+type reviewRequestEdges []struct {
+	Cursor githubv4.String
+	Node   struct {
+		PullRequest struct {
+			CreatedAt  githubv4.DateTime
+			Title      string
+			Url        string
+			Repository struct {
+				Name       string
+				IsArchived bool
+			}
+			ReviewRequests struct {
+				Nodes []struct {
+					RequestedReviewer struct {
+						User struct {
+							Login string
+						} `graphql:"... on User"`
+					}
+				}
+			} `graphql:"reviewRequests(first: 100)"`
+			TimelineItems struct {
+				Nodes []struct {
+					Typename             string `graphql:"__typename"`
+					ReviewRequestedEvent struct {
+						CreatedAt         githubv4.DateTime
+						RequestedReviewer struct {
+							User struct {
+								Login string
+							} `graphql:"... on User"`
+						}
+					} `graphql:"... on ReviewRequestedEvent"`
+					PullRequestReview struct {
+						SubmittedAt githubv4.DateTime
+						State       string
+						Author      struct {
+							Login string
+						}
+					} `graphql:"... on PullRequestReview"`
+				}
+			} `graphql:"timelineItems(first: 100, itemTypes: [REVIEW_REQUESTED_EVENT, PULL_REQUEST_REVIEW])"`
+		} `graphql:"... on PullRequest"`
+	}
+}
+
+var reviewRequestsQuery struct {
+	Search struct {
+		Edges    reviewRequestEdges
+		PageInfo cmd.PageInfo
+	} `graphql:"search(first: $first, after: $after, query: $query, type: $type)"`
+}
+
+/*
+ * define the function that is used to gather the review workload for the named user(s)
+ * in the repositories managed by the named team
+ */
+func reviewLoad() map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	// define the start and end time of our query window
+	startDateTime, endDateTime := utils.GetQueryTimeWindow()
+	// get the list of users we're interested in and the list of repositories that are
+	// managed by the team we're looking for
+	gitHubIdList := utils.GetUserIdList()
+	teamName, repositoryList := utils.GetTeamRepos()
+	// and the threshold we'll use to decide which pending review requests should be listed
+	staleAfterStr := viper.GetString("staleAfter")
+	staleAfterDuration := utils.ParseDuration(staleAfterStr)
+	now := time.Now()
+	// initialize the maps we'll use to track the pending requests, completed reviews,
+	// and times to review for each user, along with the list of unanswered requests
+	pendingByUser := map[string]int{}
+	completedByUser := map[string]int{}
+	timesToReviewByUser := map[string][]time.Duration{}
+	unansweredRequests := []map[string]interface{}{}
+	// initialize the vars map that we'll use when making our queries
+	vars := map[string]interface{}{}
+	vars["first"] = githubv4.Int(100)
+	vars["type"] = githubv4.SearchTypeIssue
+	// loop over the input organization names
+	for _, orgName := range utils.GetOrgNameList() {
+		// define the queries to run for each organization; the first looks for the open PRs
+		// (which is where any pending review requests will be found) while the second looks
+		// for the PRs that were updated within our time window (which is where any reviews
+		// completed within our time window will be found)
+		queries := map[string]githubv4.String{
+			"open": githubv4.String(fmt.Sprintf("org:%s type:pr state:open -label:backlog", orgName)),
+			"updated": githubv4.String(fmt.Sprintf("org:%s type:pr -label:backlog updated:>=%s created:<%s", orgName,
+				startDateTime.Format(cmd.ISO8601_FormatStr), endDateTime.Format(cmd.ISO8601_FormatStr))),
+		}
+		for queryName, query := range queries {
+			vars["query"] = query
+			// define the variable used to track the cursor values as we go
+			vars["after"] = githubv4.String("")
+			for {
+				// run our query, returning the results in the reviewRequestsQuery struct
+				err := client.Query(context.Background(), &reviewRequestsQuery, vars)
+				if err != nil {
+					// Handle error.
+					fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(os.Stderr, ".")
+				for _, edge := range reviewRequestsQuery.Search.Edges {
+					pullRequest := edge.Node.PullRequest
+					// if the repository for this PR is not managed by the team we're interested
+					// in (or if it is archived), then skip it
+					orgAndRepoName := orgName + "/" + pullRequest.Repository.Name
+					if len(pullRequest.Repository.Name) == 0 || utils.FindIndexOf(orgAndRepoName, repositoryList) < 0 ||
+						pullRequest.Repository.IsArchived {
+						continue
+					}
+					// walk through the timeline for this PR (in chronological order), matching each
+					// review with the (earliest) outstanding review request for the same user (the
+					// requests and reviews for any of a user's logins are credited to that user)
+					requestedAt := map[string]time.Time{}
+					for _, item := range pullRequest.TimelineItems.Nodes {
+						switch item.Typename {
+						case "ReviewRequestedEvent":
+							reviewer := utils.GetPersonId(item.ReviewRequestedEvent.RequestedReviewer.User.Login)
+							if _, ok := requestedAt[reviewer]; !ok && reviewer != "" {
+								requestedAt[reviewer] = item.ReviewRequestedEvent.CreatedAt.Time
+							}
+						case "PullRequestReview":
+							review := item.PullRequestReview
+							// skip pending reviews (which haven't been submitted yet)
+							if review.SubmittedAt.IsZero() || review.State == "PENDING" {
+								continue
+							}
+							reviewer := utils.GetPersonId(review.Author.Login)
+							reviewRequestedAt, requested := requestedAt[reviewer]
+							delete(requestedAt, reviewer)
+							// only the reviews submitted by one of our users within our time
+							// window (found by the second query) are counted
+							if queryName != "updated" || !utils.SliceContains(gitHubIdList, reviewer) ||
+								review.SubmittedAt.Before(startDateTime.Time) || !review.SubmittedAt.Before(endDateTime.Time) {
+								continue
+							}
+							completedByUser[reviewer]++
+							if requested {
+								timesToReviewByUser[reviewer] = append(timesToReviewByUser[reviewer], review.SubmittedAt.Sub(reviewRequestedAt))
+							}
+						}
+					}
+					// then, for open PRs, count the review requests that are still pending
+					if queryName != "open" {
+						continue
+					}
+					for _, request := range pullRequest.ReviewRequests.Nodes {
+						reviewer := utils.GetPersonId(request.RequestedReviewer.User.Login)
+						if !utils.SliceContains(gitHubIdList, reviewer) {
+							continue
+						}
+						pendingByUser[reviewer]++
+						// if we can't find the event for this request in the PR's timeline,
+						// then assume it was requested when the PR was created
+						reviewRequestedAt, ok := requestedAt[reviewer]
+						if !ok {
+							reviewRequestedAt = pullRequest.CreatedAt.Time
+						}
+						if waiting := now.Sub(reviewRequestedAt); waiting > staleAfterDuration {
+							unansweredRequests = append(unansweredRequests, map[string]interface{}{
+								"reviewer":       reviewer,
+								"url":            pullRequest.Url,
+								"title":          pullRequest.Title,
+								"repositoryName": pullRequest.Repository.Name,
+								"requestedAt":    utils.InTimeZone(reviewRequestedAt),
+								"waiting":        utils.JsonDuration{Duration: waiting},
+							})
+						}
+					}
+				}
+				// if we've reached the end of the list of PRs, break out of the loop
+				pageInfo := reviewRequestsQuery.Search.PageInfo
+				if !pageInfo.HasNextPage {
+					break
+				}
+				vars["after"] = pageInfo.EndCursor
+			}
+		}
+	}
+	// construct the summary for each user
+	byUser := map[string]interface{}{}
+	for _, gitHubId := range gitHubIdList {
+		byUser[gitHubId] = map[string]interface{}{
+			"pendingRequests":    pendingByUser[gitHubId],
+			"reviewsCompleted":   completedByUser[gitHubId],
+			"requestedReviews":   len(timesToReviewByUser[gitHubId]),
+			"medianTimeToReview": utils.JsonDuration{Duration: utils.GetMedianDuration(timesToReviewByUser[gitHubId])},
+		}
+	}
+	// sort the unanswered requests from the longest waiting to the shortest
+	sort.Slice(unansweredRequests, func(i, j int) bool {
+		return unansweredRequests[i]["waiting"].(utils.JsonDuration).Duration > unansweredRequests[j]["waiting"].(utils.JsonDuration).Duration
+	})
+	fmt.Fprintf(os.Stderr, "\nFound %d review requests waiting longer than %s in repositories managed by the '%s' team\n",
+		len(unansweredRequests), staleAfterStr, teamName)
+	// and return the results
+	return map[string]interface{}{"title": "Review Workload",
+		"start": startDateTime.Format(cmd.ISO8601_FormatStr), "end": endDateTime.Format(cmd.ISO8601_FormatStr),
+		"byUser": byUser, "staleAfter": staleAfterStr, "unansweredRequests": unansweredRequests}
+}
@@ -0,0 +1,298 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// reviewMatrixCmd represents the 'reviewMatrix' command
+var (
+	matrixFormat     string
+	includeOutsiders bool
+	reviewMatrixCmd  = &cobra.Command{
+		Use:   "reviewMatrix",
+		Short: "Generates a matrix showing who reviews whose pull requests",
+		Long: `Constructs an author by reviewer matrix containing the number of reviews that
+each of the input users performed on the pull requests made by each of the
+other input users (and, optionally, by users outside of that list) in the
+named set of GitHub organizations; the output (which can be formatted as
+JSON, CSV, or a Graphviz DOT graph) also highlights any isolated groups of
+users (who only review each other) and, for each user, the other users who
+never reviewed any of their pull requests.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			switch strings.ToLower(viper.GetString("matrixFormat")) {
+			case "", "json":
+				utils.DumpMapAsJSON(utils.GetResultsByTeam(reviewMatrix))
+			case "csv":
+				utils.CheckSingleTeamFormat("csv")
+				utils.DumpText(reviewMatrixAsCSV(reviewMatrix()))
+			case "dot":
+				utils.CheckSingleTeamFormat("dot")
+				utils.DumpText(reviewMatrixAsDOT(reviewMatrix()))
+			default:
+				fmt.Fprintf(os.Stderr, "ERROR: unrecognized output format '%s'; expected 'json', 'csv', or 'dot'\n", viper.GetString("matrixFormat"))
+				os.Exit(-9)
+			}
+		},
+	}
+)
+
+func init() {
+	cmd.UserCmd.AddCommand(reviewMatrixCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	reviewMatrixCmd.Flags().StringVar(&matrixFormat, "format", "json", "output format for the matrix (json, csv, or dot)")
+	reviewMatrixCmd.Flags().BoolVar(&includeOutsiders, "include-outsiders", false, "include PRs made by authors outside of the list of users")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("matrixFormat", reviewMatrixCmd.Flags().Lookup("format"))
+	viper.BindPFlag("includeOutsiders", reviewMatrixCmd.Flags().Lookup("include-outsiders"))
+}
+
+/*
+ * a utility function that returns the groups of users that only review each other; these
+ * are the connected components of the (undirected) graph where there is an edge between
+ * two users if either has reviewed a pull request made by the other, and we only return
+ * them if there is more than one such group (otherwise the whole team is one group)
+ */
+func getIsolatedGroups(members []string, matrix map[string]map[string]int) [][]string {
+	// first, build up the list of neighbors for each user
+	neighbors := map[string][]string{}
+	for author, reviewCounts := range matrix {
+		for reviewer := range reviewCounts {
+			if utils.SliceContains(members, author) && utils.SliceContains(members, reviewer) {
+				neighbors[author] = append(neighbors[author], reviewer)
+				neighbors[reviewer] = append(neighbors[reviewer], author)
+			}
+		}
+	}
+	// then walk that graph to find the groups of connected users (skipping any users who
+	// didn't review, and weren't reviewed by, anyone)
+	visited := map[string]bool{}
+	groups := [][]string{}
+	for _, member := range members {
+		if visited[member] || len(neighbors[member]) == 0 {
+			continue
+		}
+		group := []string{}
+		toVisit := []string{member}
+		visited[member] = true
+		for len(toVisit) > 0 {
+			current := toVisit[0]
+			toVisit = toVisit[1:]
+			group = append(group, current)
+			for _, neighbor := range neighbors[current] {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					toVisit = append(toVisit, neighbor)
+				}
+			}
+		}
+		sort.Strings(group)
+		groups = append(groups, group)
+	}
+	if len(groups) < 2 {
+		return [][]string{}
+	}
+	return groups
+}
+
+/*
+ * define the function that is used to construct the author by reviewer matrix
+ * for the pull request reviews made by the named user(s) in the named org(s)
+ */
+func reviewMatrix() map[string]interface{} {
+	// first, get a new GitHub GraphQL API client
+	client := utils.GetAuthenticatedClient()
+	// and then get the list of organization IDs that we want to query
+	orgIdList := utils.GetOrgIdList(client)
+	// define the start and end time of our query window
+	startDateTime, endDateTime := utils.GetQueryTimeWindow()
+	// get the list of users (the reviewers) that we're interested in
+	gitHubIdList := utils.GetUserIdList()
+	outsidersIncluded := viper.GetBool("includeOutsiders")
+	// initialize the vars map that we'll use when making our query for PR review contributions
+	vars := map[string]interface{}{
+		"from":  startDateTime,
+		"to":    endDateTime,
+		"first": githubv4.Int(100),
+	}
+	// initialize the matrix (a map of authors to a map of reviewers to review counts)
+	matrix := map[string]map[string]int{}
+	outsiders := []string{}
+	// loop over the list of GitHub IDs
+	for _, gitHubId := range gitHubIdList {
+		// loop over all of the GitHub logins for this user (for users with more than
+		// one GitHub account), gathering the results for all of them under this user
+		for _, login := range utils.GetUserLogins(gitHubId) {
+			// set the login value for this query to the current login
+			vars["login"] = githubv4.String(login)
+			// and loop over the list of Org IDs
+			for _, orgId := range orgIdList {
+				// set the "organizationID" field and (re)set the "after" field its
+				// initial value in the "vars" map
+				vars["organizationID"] = orgId
+				// define the variable used to track the cursor values as we go
+				lastCursor := githubv4.String("")
+				for {
+					// set the "after" field to our current "lastCursor" value
+					vars["after"] = lastCursor
+					// run our query, returning the results in the PullRequestReviewsPerformedQuery struct
+					err := client.Query(context.Background(), &pullRequestReviewsPerformedQuery, vars)
+					if err != nil {
+						// Handle error.
+						fmt.Fprintln(os.Stderr, err)
+						os.Exit(1)
+					}
+					edges := pullRequestReviewsPerformedQuery.User.ContributionsCollection.PullRequestReviewContributions.Edges
+					// if nothing was returned, then we've found all of the contributions
+					// from this user to this organization so break out of the loop
+					if len(edges) == 0 {
+						break
+					}
+					fmt.Fprintf(os.Stderr, ".")
+					for _, edge := range edges {
+						lastCursor = edge.Cursor
+						// (the pull requests opened using any of a user's logins are credited to that user)
+						author := utils.GetPersonId(edge.Node.PullRequest.Author.Login)
+						// skip reviews of the reviewer's own pull requests (and of pull requests
+						// from deleted accounts)
+						if author == "" || author == gitHubId {
+							continue
+						}
+						// and skip reviews of pull requests made by outsiders (unless we were
+						// asked to include them)
+						if !utils.SliceContains(gitHubIdList, author) {
+							if !outsidersIncluded {
+								continue
+							}
+							if !utils.SliceContains(outsiders, author) {
+								outsiders = append(outsiders, author)
+							}
+						}
+						if _, ok := matrix[author]; !ok {
+							matrix[author] = map[string]int{}
+						}
+						matrix[author][gitHubId]++
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(outsiders)
+	// next, for each user, find the other users who never reviewed their pull requests
+	neverReviewedBy := map[string]interface{}{}
+	for _, author := range gitHubIdList {
+		reviewers := []string{}
+		for _, reviewer := range gitHubIdList {
+			if reviewer != author && matrix[author][reviewer] == 0 {
+				reviewers = append(reviewers, reviewer)
+			}
+		}
+		neverReviewedBy[author] = reviewers
+	}
+	// convert the matrix into a form that we can output
+	matrixMap := map[string]interface{}{}
+	for author, reviewCounts := range matrix {
+		countsMap := map[string]interface{}{}
+		for reviewer, count := range reviewCounts {
+			countsMap[reviewer] = count
+		}
+		matrixMap[author] = countsMap
+	}
+	// and return the results
+	return map[string]interface{}{"title": "Review Matrix",
+		"start": startDateTime.Format(cmd.ISO8601_FormatStr), "end": endDateTime.Format(cmd.ISO8601_FormatStr),
+		"members": gitHubIdList, "outsiders": outsiders, "matrix": matrixMap,
+		"isolatedGroups": getIsolatedGroups(gitHubIdList, matrix), "neverReviewedBy": neverReviewedBy}
+}
+
+/*
+ * a utility function that returns the review count for the input author and reviewer
+ * from the matrix in the results returned by the reviewMatrix function (above)
+ */
+func getReviewCount(results map[string]interface{}, author string, reviewer string) int {
+	if countsMap, ok := results["matrix"].(map[string]interface{})[author].(map[string]interface{}); ok {
+		if count, ok := countsMap[reviewer].(int); ok {
+			return count
+		}
+	}
+	return 0
+}
+
+/*
+ * a function that formats the results returned by the reviewMatrix function (above) as CSV,
+ * with one row for each author and one column for each reviewer
+ */
+func reviewMatrixAsCSV(results map[string]interface{}) string {
+	members := results["members"].([]string)
+	authors := append(append([]string{}, members...), results["outsiders"].([]string)...)
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(append([]string{"author"}, members...))
+	for _, author := range authors {
+		row := []string{author}
+		for _, reviewer := range members {
+			row = append(row, strconv.Itoa(getReviewCount(results, author, reviewer)))
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return buf.String()
+}
+
+/*
+ * a function that formats the results returned by the reviewMatrix function (above) as a
+ * Graphviz DOT graph, with an edge from each reviewer to each author they reviewed (labelled
+ * with the number of reviews); each isolated group of users is drawn as a separate (dashed)
+ * cluster and any outsiders are drawn as boxes
+ */
+func reviewMatrixAsDOT(results map[string]interface{}) string {
+	members := results["members"].([]string)
+	outsiders := results["outsiders"].([]string)
+	var buf bytes.Buffer
+	buf.WriteString("digraph reviewMatrix {\n")
+	for idx, group := range results["isolatedGroups"].([][]string) {
+		fmt.Fprintf(&buf, "  subgraph cluster_%d {\n    label=\"isolated group %d\";\n    style=dashed;\n    color=red;\n", idx+1, idx+1)
+		for _, member := range group {
+			fmt.Fprintf(&buf, "    %q;\n", member)
+		}
+		buf.WriteString("  }\n")
+	}
+	for _, member := range members {
+		fmt.Fprintf(&buf, "  %q;\n", member)
+	}
+	for _, outsider := range outsiders {
+		fmt.Fprintf(&buf, "  %q [shape=box];\n", outsider)
+	}
+	for _, author := range append(append([]string{}, members...), outsiders...) {
+		for _, reviewer := range members {
+			if count := getReviewCount(results, author, reviewer); count > 0 {
+				fmt.Fprintf(&buf, "  %q -> %q [label=\"%d\"];\n", reviewer, author, count)
+			}
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
@@ -0,0 +1,276 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tjmcs/get-gh-info/cmd"
+	"github.com/tjmcs/get-gh-info/utils"
+)
+
+// timelineCmd represents the 'timeline' command
+var (
+	timelineFormat string
+	timelineRollup string
+	timelineCmd    = &cobra.Command{
+		Use:   "timeline",
+		Short: "Generates a chronological timeline of the contributions made",
+		Long: `Constructs a single, time-ordered stream of the contributions made by each of
+the input users (the commits they made, the pull requests they opened, the
+pull request reviews they performed, the issues they opened, and the comments
+they made on issues) in the named set of GitHub organizations, along with a
+daily or weekly rollup of those contributions; the output can be formatted
+as JSON, as JSON lines (one event per line), or as a Markdown log.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			rollup := strings.ToLower(viper.GetString("timelineRollup"))
+			if rollup != "daily" && rollup != "weekly" {
+				fmt.Fprintf(os.Stderr, "ERROR: unrecognized rollup period '%s'; expected 'daily' or 'weekly'\n", viper.GetString("timelineRollup"))
+				os.Exit(-9)
+			}
+			switch strings.ToLower(viper.GetString("timelineFormat")) {
+			case "", "json":
+				utils.DumpMapAsJSON(utils.GetResultsByTeam(func() map[string]interface{} {
+					return timeline(rollup)
+				}))
+			case "jsonl":
+				utils.CheckSingleTeamFormat("jsonl")
+				utils.DumpText(timelineAsJSONLines(timeline(rollup)))
+			case "markdown", "md":
+				utils.CheckSingleTeamFormat("markdown")
+				utils.DumpText(timelineAsMarkdown(timeline(rollup)))
+			default:
+				fmt.Fprintf(os.Stderr, "ERROR: unrecognized output format '%s'; expected 'json', 'jsonl', or 'markdown'\n", viper.GetString("timelineFormat"))
+				os.Exit(-9)
+			}
+		},
+	}
+)
+
+func init() {
+	cmd.UserCmd.AddCommand(timelineCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	timelineCmd.Flags().StringVar(&timelineFormat, "format", "json", "output format for the timeline (json, jsonl, or markdown)")
+	timelineCmd.Flags().StringVar(&timelineRollup, "rollup", "weekly", "period used to roll up the events in the timeline (daily or weekly)")
+
+	// bind the flags defined above to viper (so that we can use viper to retrieve the values)
+	viper.BindPFlag("timelineFormat", timelineCmd.Flags().Lookup("format"))
+	viper.BindPFlag("timelineRollup", timelineCmd.Flags().Lookup("rollup"))
+}
+
+/*
+ * define the types of events that make up the timeline, along with the function used
+ * to gather the contributions of each type, the key used to find the timestamp for each
+ * of those contributions, and the label used for them in the Markdown output
+ */
+type timelineEventType struct {
+	eventType    string
+	timestampKey string
+	label        string
+	getResults   func() map[string]interface{}
+}
+
+var timelineEventTypes = []timelineEventType{
+	{"commit", "contributedAt", "commits", contribs},
+	{"pullRequest", "createdAt", "pull requests opened", prList},
+	{"review", "occurredAt", "reviews", prReviews},
+	{"issue", "createdAt", "issues opened", issueList},
+	{"issueComment", "createdAt", "issue comments", issueCommentsList},
+}
+
+// the format used for the period names in the rollups
+const timelinePeriodFormat = "2006-01-02"
+
+/*
+ * a utility function that returns the list of contributions for the named user from
+ * the results returned by one of the list functions (prList, prReviews, etc.)
+ */
+func getUserContribList(results map[string]interface{}, gitHubId string) []map[string]interface{} {
+	if byUser, ok := results["ByUser"].([]map[string]interface{}); ok {
+		for _, userEntry := range byUser {
+			if contribList, ok := userEntry[gitHubId].([]map[string]interface{}); ok {
+				return contribList
+			}
+		}
+	}
+	return []map[string]interface{}{}
+}
+
+/*
+ * a utility function that returns the number of contributions represented by an event
+ * in the timeline (commit events are grouped by date and repository, so a single event
+ * may represent several commits)
+ */
+func getEventCount(event map[string]interface{}) int {
+	if count, ok := event["count"].(int); ok {
+		return count
+	}
+	return 1
+}
+
+/*
+ * define the function that is used to construct the timeline (and the rollups) for
+ * the contributions made by the named user(s) against repositories under the named
+ * org(s)
+ */
+func timeline(rollup string) map[string]interface{} {
+	// define the start and end time of our query window
+	startDateTime, endDateTime := utils.GetQueryTimeWindow()
+	gitHubIdList := utils.GetUserIdList()
+	// gather the contributions of each type (for all of the users)
+	resultsByType := map[string]map[string]interface{}{}
+	for _, eventType := range timelineEventTypes {
+		resultsByType[eventType.eventType] = eventType.getResults()
+	}
+	byUser := map[string]interface{}{}
+	for _, gitHubId := range gitHubIdList {
+		// merge the contributions of each type made by this user into a single list of events
+		events := []map[string]interface{}{}
+		for _, eventType := range timelineEventTypes {
+			for _, contrib := range getUserContribList(resultsByType[eventType.eventType], gitHubId) {
+				occurredAt, ok := contrib[eventType.timestampKey].(time.Time)
+				if !ok || occurredAt.IsZero() {
+					continue
+				}
+				event := map[string]interface{}{
+					"type":           eventType.eventType,
+					"occurredAt":     occurredAt,
+					"period":         utils.GetPeriodStartDate(occurredAt, rollup).Format(timelinePeriodFormat),
+					"repositoryName": contrib["repositoryName"],
+				}
+				if title, ok := contrib["title"]; ok {
+					event["title"] = title
+				} else if title, ok := contrib["issueTitle"]; ok {
+					event["title"] = title
+				}
+				if url, ok := contrib["url"]; ok {
+					event["url"] = url
+				}
+				if count, ok := contrib["numContributions"]; ok {
+					event["count"] = count
+				}
+				events = append(events, event)
+			}
+		}
+		// sort those events chronologically
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i]["occurredAt"].(time.Time).Before(events[j]["occurredAt"].(time.Time))
+		})
+		// and roll them up (by type) into daily or weekly buckets
+		rollups := []map[string]interface{}{}
+		for _, event := range events {
+			if len(rollups) == 0 || rollups[len(rollups)-1]["period"] != event["period"] {
+				counts := map[string]int{}
+				for _, eventType := range timelineEventTypes {
+					counts[eventType.eventType] = 0
+				}
+				rollups = append(rollups, map[string]interface{}{"period": event["period"], "counts": counts})
+			}
+			rollups[len(rollups)-1]["counts"].(map[string]int)[event["type"].(string)] += getEventCount(event)
+		}
+		byUser[gitHubId] = map[string]interface{}{
+			"events":  events,
+			"rollups": rollups,
+		}
+	}
+	// and return the results
+	return map[string]interface{}{"title": "User Activity Timeline",
+		"start": startDateTime.Format(cmd.ISO8601_FormatStr), "end": endDateTime.Format(cmd.ISO8601_FormatStr),
+		"members": gitHubIdList, "rollup": rollup, "byUser": byUser}
+}
+
+/*
+ * a function that formats the results returned by the timeline function (above) as JSON
+ * lines; for each user, the rollup for each period is output (as an event with a type
+ * of "rollup") followed by the events that occurred in that period
+ */
+func timelineAsJSONLines(results map[string]interface{}) string {
+	var buf bytes.Buffer
+	writeLine := func(line map[string]interface{}) {
+		jsonBytes, err := json.Marshal(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: unable to marshal results to JSON: %v", err)
+			os.Exit(-7)
+		}
+		buf.Write(jsonBytes)
+		buf.WriteString("\n")
+	}
+	for _, gitHubId := range results["members"].([]string) {
+		userTimeline := results["byUser"].(map[string]interface{})[gitHubId].(map[string]interface{})
+		rollups := userTimeline["rollups"].([]map[string]interface{})
+		rollupIdx := -1
+		for _, event := range userTimeline["events"].([]map[string]interface{}) {
+			if rollupIdx < 0 || rollups[rollupIdx]["period"] != event["period"] {
+				rollupIdx++
+				writeLine(map[string]interface{}{"user": gitHubId, "type": "rollup",
+					"period": rollups[rollupIdx]["period"], "counts": rollups[rollupIdx]["counts"]})
+			}
+			line := map[string]interface{}{"user": gitHubId}
+			for key, value := range event {
+				line[key] = value
+			}
+			writeLine(line)
+		}
+	}
+	return buf.String()
+}
+
+/*
+ * a function that formats the results returned by the timeline function (above) as a
+ * Markdown log, with a section for each user, a heading (summarizing the rollup) for
+ * each period, and a bullet for each event in that period
+ */
+func timelineAsMarkdown(results map[string]interface{}) string {
+	var buf bytes.Buffer
+	periodName := "Day"
+	if results["rollup"] == "weekly" {
+		periodName = "Week of"
+	}
+	fmt.Fprintf(&buf, "# %s (%s to %s)\n", results["title"], results["start"], results["end"])
+	for _, gitHubId := range results["members"].([]string) {
+		fmt.Fprintf(&buf, "\n## %s\n", gitHubId)
+		userTimeline := results["byUser"].(map[string]interface{})[gitHubId].(map[string]interface{})
+		rollups := userTimeline["rollups"].([]map[string]interface{})
+		if len(rollups) == 0 {
+			buf.WriteString("\nNo activity found.\n")
+			continue
+		}
+		rollupIdx := -1
+		for _, event := range userTimeline["events"].([]map[string]interface{}) {
+			if rollupIdx < 0 || rollups[rollupIdx]["period"] != event["period"] {
+				rollupIdx++
+				counts := rollups[rollupIdx]["counts"].(map[string]int)
+				summary := []string{}
+				for _, eventType := range timelineEventTypes {
+					if counts[eventType.eventType] > 0 {
+						summary = append(summary, fmt.Sprintf("%d %s", counts[eventType.eventType], eventType.label))
+					}
+				}
+				fmt.Fprintf(&buf, "\n### %s %s (%s)\n\n", periodName, rollups[rollupIdx]["period"], strings.Join(summary, ", "))
+			}
+			occurredAt := event["occurredAt"].(time.Time).Format("2006-01-02 15:04")
+			if event["type"] == "commit" {
+				fmt.Fprintf(&buf, "- %s: %d commit(s) to %v\n", occurredAt, getEventCount(event), event["repositoryName"])
+				continue
+			}
+			fmt.Fprintf(&buf, "- %s: %s [%v](%v) in %v\n", occurredAt, event["type"], event["title"], event["url"], event["repositoryName"])
+		}
+	}
+	return buf.String()
+}
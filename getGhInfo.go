@@ -5,9 +5,13 @@ package main
 
 import (
 	"github.com/tjmcs/get-gh-info/cmd"
+	_ "github.com/tjmcs/get-gh-info/cmd/config"
 	_ "github.com/tjmcs/get-gh-info/cmd/repo"
+	_ "github.com/tjmcs/get-gh-info/cmd/repo/delivery"
 	_ "github.com/tjmcs/get-gh-info/cmd/repo/issues"
+	_ "github.com/tjmcs/get-gh-info/cmd/repo/mapping"
 	_ "github.com/tjmcs/get-gh-info/cmd/repo/pulls"
+	_ "github.com/tjmcs/get-gh-info/cmd/team"
 	_ "github.com/tjmcs/get-gh-info/cmd/user"
 )
 
@@ -0,0 +1,290 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/viper"
+)
+
+/*
+ * define the struct that we'll use to retrieve the CODEOWNERS file (if any) for
+ * each of the repositories in an organization; GitHub looks for this file in the
+ * '.github' directory, in the root directory, and in the 'docs' directory (in that
+ * order), so we retrieve all three and use the first one that is found
+ */
+type codeownersBlob struct {
+	Blob struct {
+		Text string
+	} `graphql:"... on Blob"`
+}
+
+type codeownersBody struct {
+	Nodes []struct {
+		Name             string
+		Url              string
+		IsArchived       bool
+		IsPrivate        bool
+		GitHubCodeowners codeownersBlob `graphql:"gitHubCodeowners: object(expression: \"HEAD:.github/CODEOWNERS\")"`
+		RootCodeowners   codeownersBlob `graphql:"rootCodeowners: object(expression: \"HEAD:CODEOWNERS\")"`
+		DocsCodeowners   codeownersBlob `graphql:"docsCodeowners: object(expression: \"HEAD:docs/CODEOWNERS\")"`
+	}
+	PageInfo struct {
+		EndCursor   githubv4.String
+		HasNextPage bool
+	}
+}
+
+var firstCodeownersQuery struct {
+	Organization struct {
+		Repositories struct {
+			codeownersBody
+		} `graphql:"repositories(first: $first)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+var codeownersQuery struct {
+	Organization struct {
+		Repositories struct {
+			codeownersBody
+		} `graphql:"repositories(first: $first, after: $after)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+// the owners declared in the CODEOWNERS file for a repository
+type RepoCodeowners struct {
+	Name          string
+	Url           string
+	HasCodeowners bool
+	Owners        []string
+}
+
+// the CODEOWNERS for the repositories in the named organizations (so that we
+// only need to retrieve them once)
+var repoCodeownersList []RepoCodeowners
+
+// the members of each of the teams in the configuration file (so that we only need
+// to build those lists once, rather than once for every repository)
+var codeownersTeamMembers map[string][]map[string]string
+
+// the values supported for the 'ownership_source' configuration value
+var OwnershipSources = []string{"mapping", "codeowners", "both"}
+
+/*
+ * returns the source used to determine which repositories are owned by each team;
+ * this can be the repository mapping file ("mapping", the default), the CODEOWNERS
+ * files in the repositories themselves ("codeowners"), or both
+ */
+func getOwnershipSource() string {
+	ownershipSource := strings.ToLower(viper.GetString("ownership_source"))
+	if ownershipSource == "" {
+		return "mapping"
+	}
+	if !SliceContains(OwnershipSources, ownershipSource) {
+		fmt.Fprintf(os.Stderr, "ERROR: unrecognized ownership source '%s'; expected 'mapping', 'codeowners', or 'both'\n", ownershipSource)
+		os.Exit(-9)
+	}
+	return ownershipSource
+}
+
+/*
+ * a utility function that parses the contents of a CODEOWNERS file, returning the
+ * (lower-cased) owners of the repository as a whole; these are the owners listed for
+ * the last '*' pattern in the file or, if there is no such pattern, all of the owners
+ * listed in the file (team owners are of the form 'org/slug', while owners listed by
+ * email address are mapped back to a user using the 'emails' defined for each user)
+ */
+func parseCodeowners(text string) []string {
+	defaultOwners := []string(nil)
+	allOwners := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		// strip out any comments (and skip any lines that are empty as a result)
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		owners := []string{}
+		for _, owner := range fields[1:] {
+			if !strings.Contains(owner, "@") {
+				continue
+			}
+			owner = strings.ToLower(strings.TrimPrefix(owner, "@"))
+			owners = append(owners, owner)
+			if !SliceContains(allOwners, owner) {
+				allOwners = append(allOwners, owner)
+			}
+		}
+		if fields[0] == "*" || fields[0] == "/*" || fields[0] == "/" {
+			defaultOwners = owners
+		}
+	}
+	if defaultOwners != nil {
+		return defaultOwners
+	}
+	return allOwners
+}
+
+/*
+ * a function that returns the owners declared in the CODEOWNERS file for each of the
+ * (non-archived) repositories in the named organizations
+ */
+func GetRepoCodeowners() []RepoCodeowners {
+	if repoCodeownersList != nil {
+		return repoCodeownersList
+	}
+	client := GetAuthenticatedClient()
+	excludePrivateRepos := viper.GetBool("excludePrivateRepos")
+	repoCodeownersList = []RepoCodeowners{}
+	for _, orgName := range GetOrgNameList() {
+		vars := map[string]interface{}{
+			"orgname": githubv4.String(orgName),
+			"first":   githubv4.Int(50),
+		}
+		firstPage := true
+		for {
+			var err error
+			var repositories codeownersBody
+			if firstPage {
+				err = client.Query(context.Background(), &firstCodeownersQuery, vars)
+				repositories = firstCodeownersQuery.Organization.Repositories.codeownersBody
+				firstPage = false
+			} else {
+				err = client.Query(context.Background(), &codeownersQuery, vars)
+				repositories = codeownersQuery.Organization.Repositories.codeownersBody
+			}
+			if err != nil {
+				// Handle error.
+				fmt.Fprintf(os.Stderr, "ERR: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, ".")
+			for _, repository := range repositories.Nodes {
+				if repository.IsArchived || (excludePrivateRepos && repository.IsPrivate) {
+					continue
+				}
+				repoCodeowners := RepoCodeowners{Name: repository.Name, Url: repository.Url, Owners: []string{}}
+				for _, text := range []string{repository.GitHubCodeowners.Blob.Text,
+					repository.RootCodeowners.Blob.Text, repository.DocsCodeowners.Blob.Text} {
+					if text != "" {
+						repoCodeowners.HasCodeowners = true
+						repoCodeowners.Owners = parseCodeowners(text)
+						break
+					}
+				}
+				repoCodeownersList = append(repoCodeownersList, repoCodeowners)
+			}
+			// if we've reached the end of the list of repositories, break out of the loop
+			if !repositories.PageInfo.HasNextPage {
+				break
+			}
+			// set the "after" field to the "EndCursor" from the pageInfo structure so
+			// we will get the next page of results when we run the query again
+			vars["after"] = repositories.PageInfo.EndCursor
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+	return repoCodeownersList
+}
+
+/*
+ * a utility function that returns true if the named team (from the configuration file)
+ * is one of the input owners, either because the corresponding GitHub team (the team
+ * with the same slug, or the slug defined for that team in the 'team_slugs' map) is
+ * listed as an owner or because one of the members of that team is (using any of their
+ * GitHub logins or email addresses)
+ */
+func isTeamInOwners(teamName string, teamMembers []map[string]string, owners []string) bool {
+	teamSlug := teamName
+	if orgName, slug, ok := ParseGitHubTeamName(teamName); ok {
+		teamSlug = orgName + "/" + slug
+	} else if slug, ok := viper.GetStringMapString("team_slugs")[teamName]; ok {
+		teamSlug = slug
+	}
+	teamSlug = strings.ToLower(teamSlug)
+	orgNameList := GetOrgNameList()
+	for _, owner := range owners {
+		if orgName, slug, found := strings.Cut(owner, "/"); found {
+			// team owners must match the slug (and the organization, if one was defined
+			// for the team, or one of the named organizations if one wasn't)
+			if owner == teamSlug {
+				return true
+			}
+			for _, configOrgName := range orgNameList {
+				if slug == teamSlug && strings.EqualFold(orgName, configOrgName) {
+					return true
+				}
+			}
+			continue
+		}
+		for _, member := range teamMembers {
+			for _, login := range GetUserLogins(member["githubid"]) {
+				if strings.EqualFold(owner, login) {
+					return true
+				}
+			}
+			if SliceContains(GetMemberEmails(member), owner) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+ * a utility function that returns a map of the names of the teams defined in the
+ * configuration file to the members of each of those teams
+ */
+func getCodeownersTeamMembers() map[string][]map[string]string {
+	if codeownersTeamMembers != nil {
+		return codeownersTeamMembers
+	}
+	codeownersTeamMembers = map[string][]map[string]string{}
+	if teamsMap, ok := viper.Get("teams").(map[string]interface{}); ok {
+		for teamName := range teamsMap {
+			_, teamMembers := GetTeamMembers(teamName)
+			codeownersTeamMembers[teamName] = teamMembers
+		}
+	}
+	return codeownersTeamMembers
+}
+
+/*
+ * a function that maps the input (CODEOWNERS) owners back to the teams defined in the
+ * configuration file, returning the (sorted) names of the teams that own the repository
+ */
+func GetCodeownersTeams(owners []string) []string {
+	owningTeams := []string{}
+	for teamName, teamMembers := range getCodeownersTeamMembers() {
+		if isTeamInOwners(teamName, teamMembers, owners) {
+			owningTeams = append(owningTeams, teamName)
+		}
+	}
+	sort.Strings(owningTeams)
+	return owningTeams
+}
+
+/*
+ * a function that returns the list of repositories (as 'org/repo' strings) that are
+ * owned by the named team according to the CODEOWNERS files in those repositories
+ */
+func GetCodeownersRepos(teamName string) []string {
+	_, teamMembers := GetTeamMembers(teamName)
+	teamRepos := []string{}
+	for _, repoCodeowners := range GetRepoCodeowners() {
+		if isTeamInOwners(teamName, teamMembers, repoCodeowners.Owners) {
+			splitString := strings.Split(repoCodeowners.Url, "/")
+			teamRepos = append(teamRepos, strings.Join(splitString[len(splitString)-2:], "/"))
+		}
+	}
+	return teamRepos
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{
+			name:     "empty file",
+			text:     "",
+			expected: []string{},
+		},
+		{
+			name:     "owners of the whole repository",
+			text:     "* @CircleCI-Public/Images @jdoe\n",
+			expected: []string{"circleci-public/images", "jdoe"},
+		},
+		{
+			name:     "the last '*' pattern wins",
+			text:     "* @org/old-team\n/docs/ @org/docs\n* @org/new-team\n",
+			expected: []string{"org/new-team"},
+		},
+		{
+			name:     "'/*' and '/' patterns also name the owners of the whole repository",
+			text:     "/docs/ @org/docs\n/* @org/images\n",
+			expected: []string{"org/images"},
+		},
+		{
+			name:     "comments and blank lines are skipped",
+			text:     "# owners of this repository\n\n* @org/images # the images team\n# * @org/commented-out\n",
+			expected: []string{"org/images"},
+		},
+		{
+			name:     "all owners (listed once each) when there is no '*' pattern",
+			text:     "/src/ @org/images @jdoe\n/docs/ @org/docs @JDoe\n",
+			expected: []string{"org/images", "jdoe", "org/docs"},
+		},
+		{
+			name:     "owners listed by email address are kept (lower-cased)",
+			text:     "* J.Doe@Example.com @org/images\n",
+			expected: []string{"j.doe@example.com", "org/images"},
+		},
+		{
+			name:     "a '*' pattern without owners leaves the repository unowned",
+			text:     "/src/ @org/images\n*\n",
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := parseCodeowners(tt.text); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("parseCodeowners(%q) = %v; expected %v", tt.text, actual, tt.expected)
+			}
+		})
+	}
+}
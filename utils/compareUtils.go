@@ -0,0 +1,129 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/spf13/viper"
+)
+
+// the keys in our results maps that hold labels (rather than values that
+// should be compared between two time windows)
+var comparisonLabelKeys = []string{"title", "start", "end", "from", "to"}
+
+/*
+ * a function that returns the time window immediately preceding the input time window
+ * (and having the same length, in days, as the input time window)
+ */
+func GetPreviousTimeWindow(startDateTime githubv4.DateTime, endDateTime githubv4.DateTime) (githubv4.DateTime, githubv4.DateTime) {
+	windowDays := int(math.Round(endDateTime.Sub(startDateTime.Time).Hours() / 24))
+	return githubv4.DateTime{Time: startDateTime.AddDate(0, 0, -windowDays)}, startDateTime
+}
+
+/*
+ * a utility function that can be used to compare a pair of (numeric) values, returning
+ * a map containing the current value, the previous value, the absolute change in value
+ * (the delta), and the percent change in value (which is nil if the previous value is zero)
+ */
+func compareNumbers(current float64, previous float64) (float64, interface{}) {
+	delta := current - previous
+	if previous == 0 {
+		return delta, nil
+	}
+	return delta, math.Round(delta/math.Abs(previous)*10000) / 100
+}
+
+/*
+ * a function that walks the results for the current time window and the results for
+ * the previous time window (which should have the same structure) and returns a result
+ * with the same structure where each of the numeric values (counts, durations, and
+ * other numbers) is replaced by a map containing the current and previous values along
+ * with the absolute and percent change between them
+ */
+func compareResults(current interface{}, previous interface{}) interface{} {
+	switch currentVal := current.(type) {
+	case map[string]interface{}:
+		previousMap, _ := previous.(map[string]interface{})
+		comparison := map[string]interface{}{}
+		for key, val := range currentVal {
+			if SliceContains(comparisonLabelKeys, key) {
+				comparison[key] = val
+				continue
+			}
+			comparison[key] = compareResults(val, previousMap[key])
+		}
+		return comparison
+	case []map[string]interface{}:
+		previousList, _ := previous.([]map[string]interface{})
+		comparison := []map[string]interface{}{}
+		for idx, val := range currentVal {
+			var previousVal map[string]interface{}
+			if idx < len(previousList) {
+				previousVal = previousList[idx]
+			}
+			comparison = append(comparison, compareResults(val, previousVal).(map[string]interface{}))
+		}
+		return comparison
+	case JsonDuration:
+		previousVal, _ := previous.(JsonDuration)
+		delta, percentChange := compareNumbers(float64(currentVal.Duration), float64(previousVal.Duration))
+		return map[string]interface{}{"current": currentVal, "previous": previousVal,
+			"delta": JsonDuration{time.Duration(delta)}, "percentChange": percentChange}
+	case int:
+		previousVal, _ := previous.(int)
+		delta, percentChange := compareNumbers(float64(currentVal), float64(previousVal))
+		return map[string]interface{}{"current": currentVal, "previous": previousVal,
+			"delta": int(delta), "percentChange": percentChange}
+	case float64:
+		previousVal, _ := previous.(float64)
+		delta, percentChange := compareNumbers(currentVal, previousVal)
+		return map[string]interface{}{"current": currentVal, "previous": previousVal,
+			"delta": math.Round(delta*100) / 100, "percentChange": percentChange}
+	}
+	// if we get here, it's not something we know how to compare, so just return
+	// the current value
+	return current
+}
+
+/*
+ * a function that runs the input query function over the query time window defined
+ * on the command-line and returns the results; if the user asked us to compare those
+ * results with the results from the previous time window (using the '--compare-previous'
+ * flag), then the same query is run over the immediately preceding time window of equal
+ * length and the two sets of results are combined (see compareResults, above)
+ */
+func getResultsWithComparison(getResults func(githubv4.DateTime, githubv4.DateTime) map[string]interface{}) map[string]interface{} {
+	// first, get the results for the query time window defined on the command-line
+	startDateTime, endDateTime := GetQueryTimeWindow()
+	currentResults := getResults(startDateTime, endDateTime)
+	// if we're not comparing these results with the previous time window, then we're done
+	if !viper.GetBool("comparePrevious") {
+		return currentResults
+	}
+	// otherwise, get the results for the previous time window
+	previousStartDateTime, previousEndDateTime := GetPreviousTimeWindow(startDateTime, endDateTime)
+	fmt.Fprintf(os.Stderr, "INFO: comparing with the previous time window, %s through %s\n",
+		previousStartDateTime.Format("2006-01-02"), previousEndDateTime.Format("2006-01-02"))
+	previousResults := getResults(previousStartDateTime, previousEndDateTime)
+	// and combine the two
+	comparison := compareResults(currentResults, previousResults).(map[string]interface{})
+	comparison["previousStart"] = previousResults["start"]
+	comparison["previousEnd"] = previousResults["end"]
+	return comparison
+}
+
+/*
+ * an exported version of the getResultsWithComparison function (above) that also
+ * supports grouping the results (see the GetResultsByGroup function)
+ */
+func GetResultsWithComparison(getResults func(githubv4.DateTime, githubv4.DateTime) map[string]interface{}) interface{} {
+	return GetResultsByGroup(func() map[string]interface{} {
+		return getResultsWithComparison(getResults)
+	})
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompareResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  interface{}
+		previous interface{}
+		expected interface{}
+	}{
+		{
+			name:     "int",
+			current:  15,
+			previous: 10,
+			expected: map[string]interface{}{"current": 15, "previous": 10, "delta": 5, "percentChange": 50.0},
+		},
+		{
+			name:     "int with a zero previous value",
+			current:  3,
+			previous: 0,
+			expected: map[string]interface{}{"current": 3, "previous": 0, "delta": 3, "percentChange": nil},
+		},
+		{
+			name:     "float64 decrease",
+			current:  1.5,
+			previous: 2.0,
+			expected: map[string]interface{}{"current": 1.5, "previous": 2.0, "delta": -0.5, "percentChange": -25.0},
+		},
+		{
+			name:     "duration",
+			current:  JsonDuration{3 * time.Hour},
+			previous: JsonDuration{2 * time.Hour},
+			expected: map[string]interface{}{"current": JsonDuration{3 * time.Hour}, "previous": JsonDuration{2 * time.Hour},
+				"delta": JsonDuration{time.Hour}, "percentChange": 50.0},
+		},
+		{
+			name:     "string values are returned unchanged",
+			current:  "org/repo",
+			previous: "org/other",
+			expected: "org/repo",
+		},
+		{
+			name:     "maps keep their labels and compare everything else",
+			current:  map[string]interface{}{"title": "Open PRs", "start": "2026-09-01", "total": 4},
+			previous: map[string]interface{}{"title": "Open PRs", "start": "2026-08-01", "total": 2},
+			expected: map[string]interface{}{"title": "Open PRs", "start": "2026-09-01",
+				"total": map[string]interface{}{"current": 4, "previous": 2, "delta": 2, "percentChange": 100.0}},
+		},
+		{
+			name:     "keys missing from the previous results compare against zero",
+			current:  map[string]interface{}{"byRepo": map[string]interface{}{"org/new": 2}},
+			previous: map[string]interface{}{"byRepo": map[string]interface{}{}},
+			expected: map[string]interface{}{"byRepo": map[string]interface{}{
+				"org/new": map[string]interface{}{"current": 2, "previous": 0, "delta": 2, "percentChange": nil}}},
+		},
+		{
+			name:     "lists of maps are compared element by element",
+			current:  []map[string]interface{}{{"from": "1d", "count": 2}, {"from": "1w", "count": 1}},
+			previous: []map[string]interface{}{{"from": "1d", "count": 1}},
+			expected: []map[string]interface{}{
+				{"from": "1d", "count": map[string]interface{}{"current": 2, "previous": 1, "delta": 1, "percentChange": 100.0}},
+				{"from": "1w", "count": map[string]interface{}{"current": 1, "previous": 0, "delta": 1, "percentChange": nil}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := compareResults(tt.current, tt.previous); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("compareResults(%v, %v) = %v; expected %v", tt.current, tt.previous, actual, tt.expected)
+			}
+		})
+	}
+}
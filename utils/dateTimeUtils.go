@@ -8,12 +8,16 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shurcooL/githubv4"
 	"github.com/spf13/viper"
 )
 
+// the time zone used for our query windows and output (see GetTimeZone)
+var timeZone *time.Location
+
 /*
  * a function that can be used to find the date corresponding to the start of
  * the week that corresponds to the input date; in this function he start of
@@ -28,12 +32,64 @@ func weekStartDate(date time.Time) time.Time {
 	offset := (int(time.Monday) - int(date.Weekday()) - 7) % 7
 	// then, add that offset to the input date to get the start of the week
 	if offset < 0 {
-		date = date.Add(time.Duration(offset*24) * time.Hour)
+		date = date.AddDate(0, 0, offset)
 	}
 	// and return the result
 	return date
 }
 
+/*
+ * returns the time zone that should be used when defining the time windows for
+ * our queries and when rendering timestamps in our output; this time zone can be
+ * passed in on the command-line (using the '--tz' flag) or defined using the
+ * 'timezone' configuration value, and defaults to UTC if neither is defined
+ */
+func GetTimeZone() *time.Location {
+	if timeZone != nil {
+		return timeZone
+	}
+	timeZoneName := viper.GetString("timezone")
+	if timeZoneName == "" {
+		timeZone = time.UTC
+		return timeZone
+	}
+	location, err := time.LoadLocation(timeZoneName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to load time zone '%s'; expected an IANA time zone name (eg. 'Asia/Singapore'); %s\n", timeZoneName, err)
+		os.Exit(-1)
+	}
+	timeZone = location
+	return timeZone
+}
+
+/*
+ * a utility function that converts a timestamp into the configured time zone (for
+ * output); zero-valued timestamps are returned unchanged
+ */
+func InTimeZone(timestamp time.Time) time.Time {
+	if timestamp.IsZero() {
+		return timestamp
+	}
+	return timestamp.In(GetTimeZone())
+}
+
+/*
+ * returns midnight at the start of the current day (in the configured time zone)
+ */
+func getCurrentDate() time.Time {
+	now := time.Now().In(GetTimeZone())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+/*
+ * adds a duration (which should be a whole number of days) to the input date as
+ * a number of calendar days, so that a window that starts at midnight still starts
+ * at midnight if it crosses a daylight saving time transition
+ */
+func addDays(date time.Time, duration time.Duration) time.Time {
+	return date.AddDate(0, 0, int(duration/(24*time.Hour)))
+}
+
 /*
  * a function that can be used to parse the "lookback time" string value can be passed in
  * on the command-line; supported time units include:
@@ -81,6 +137,28 @@ func getLookbackDuration(lookBackStr string) time.Duration {
 	return 0
 }
 
+/*
+ * an exported version of the getLookbackDuration function (above), which can be used to
+ * parse other durations that are passed in using the same format (e.g. "2d" or "1w")
+ */
+func ParseDuration(durationStr string) time.Duration {
+	return getLookbackDuration(durationStr)
+}
+
+/*
+ * returns the start of the period (either the day or, if the input period is "weekly",
+ * the week starting on Monday) that contains the input timestamp in the configured time
+ * zone; used when rolling up timestamped events into daily or weekly buckets
+ */
+func GetPeriodStartDate(timestamp time.Time, period string) time.Time {
+	timestamp = timestamp.In(GetTimeZone())
+	date := time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, timestamp.Location())
+	if period == "weekly" {
+		return weekStartDate(date)
+	}
+	return date
+}
+
 /*
  * a function that can be used to get a time window to use for our queries; note that
  * the logic around this is a lot more difficult than it might seem at first because
@@ -90,6 +168,23 @@ func getLookbackDuration(lookBackStr string) time.Duration {
  * edge cases triggering a "lookahead" mode rather than a "lookback" mode)
  */
 func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
+	// the time window is determined once (and reused by any later calls, since several
+	// of our commands gather the results from more than one query)
+	if queryTimeWindow == nil {
+		startDateTime, endDateTime := getQueryTimeWindow()
+		queryTimeWindow = []githubv4.DateTime{startDateTime, endDateTime}
+	}
+	return queryTimeWindow[0], queryTimeWindow[1]
+}
+
+// the time window for our queries (so that we only need to determine it once)
+var queryTimeWindow []githubv4.DateTime
+
+/*
+ * the function that actually determines the time window for our queries (see the
+ * GetQueryTimeWindow function, above)
+ */
+func getQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 	// setup a few variables that we'll be using in this function
 	var refDateTime time.Time
 	var startDateTime time.Time
@@ -102,8 +197,18 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 	// next, look for the "lookbackTime" value that we should use (this value can
 	// be passed in on the command-line, but defaults to the empty string)
 	lookBackStr := viper.GetString("lookbackTime")
+	// if an explicit start and/or end date or a named period was specified, then use
+	// those values to define our time window instead
+	if viper.GetString("sinceDate") != "" || viper.GetString("untilDate") != "" || viper.GetString("period") != "" {
+		if referenceDate != "" || lookBackStr != "" {
+			fmt.Fprintf(os.Stderr, "ERROR: the '--since', '--until', and '--period' flags cannot be combined with the '--ref-date' or '--lookback-time' flags\n")
+			os.Exit(-1)
+		}
+		startDateTime, endDateTime = getExplicitTimeWindow()
+		return checkQueryTimeWindow(startDateTime, endDateTime)
+	}
 	if referenceDate != "" {
-		dateTime, err := time.Parse("2006-01-02", referenceDate)
+		dateTime, err := time.ParseInLocation("2006-01-02", referenceDate, GetTimeZone())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR: unable to parse end date '%s'; expected format is '2006-01-02'\n", referenceDate)
 			os.Exit(-1)
@@ -111,9 +216,9 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 		refDateTime = dateTime
 	} else {
 		// If here, then no end-date was specified, so choose a default value of
-		// of the current day at midnight (UTC) and make that the ending date time
-		// for our query
-		refDateTime = time.Now().UTC().Truncate(time.Hour * 24)
+		// of the current day at midnight (in the configured time zone) and make that
+		// the ending date time for our query
+		refDateTime = getCurrentDate()
 	}
 	// if a lookback time was specified, then grab it
 	if lookBackStr != "" {
@@ -134,7 +239,7 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 			// for a look ahead time window
 			offset := (int(time.Monday) - int(refDateTime.Weekday()) - 7) % 7
 			if offset != 0 {
-				refDateTime = refDateTime.Add(7 * 24 * time.Hour)
+				refDateTime = refDateTime.AddDate(0, 0, 7)
 			}
 		}
 		// now, shift the reference date to the start of the week we're interested in
@@ -149,7 +254,7 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 		// time added to the start date time
 		if lookBackDuration < 0 {
 			startDateTime = refDateTime
-			refDateTime = refDateTime.Add(-lookBackDuration)
+			refDateTime = addDays(refDateTime, -lookBackDuration)
 			if showCompleteWeeksOnly {
 				// since it's an end date for the window, we just need to truncate so that we only
 				// see data from complete weeks in our output
@@ -159,14 +264,14 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 		} else {
 			// otherwise, subtract the lookback time from the reference date time to get the
 			// start of our query window
-			startDateTime = refDateTime.Add(-lookBackDuration)
+			startDateTime = addDays(refDateTime, -lookBackDuration)
 			if showCompleteWeeksOnly {
 				// if the start date is not the start of the week, then we need to shift
 				// by a week and truncate to the start of the week to ensure we only get
 				// complete weeks in our output data
 				offset := (int(time.Monday) - int(startDateTime.Weekday()) - 7) % 7
 				if offset != 0 {
-					startDateTime = weekStartDate(startDateTime.Add(7 * 24 * time.Hour))
+					startDateTime = weekStartDate(startDateTime.AddDate(0, 0, 7))
 					fmt.Fprintf(os.Stderr, "WARN: only complete weeks requested, start date set to '%s'\n", startDateTime.Format("2006-01-02"))
 				}
 			}
@@ -178,7 +283,7 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 		// is the curren date time
 		if referenceDate != "" {
 			startDateTime = refDateTime
-			endDateTime = time.Now().UTC().Truncate(time.Hour * 24)
+			endDateTime = getCurrentDate()
 			if showCompleteWeeksOnly {
 				// since it's an end date for the window, we just need to truncate so that we only
 				// see data from complete weeks in our output
@@ -190,20 +295,29 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 			// otherwise, if neither a lookback time nor a reference time was specified, then
 			// assume a default lookback time of 90 days from the current date time
 			fmt.Fprintf(os.Stderr, "WARN: no lookback time or reference date specified; using default lookback time of 90 days\n")
-			startDateTime = refDateTime.Add(-defaultLookbackDays * 24 * time.Hour)
+			startDateTime = refDateTime.AddDate(0, 0, -defaultLookbackDays)
 			if showCompleteWeeksOnly {
 				// since it's a start date for the window, we need to shift by a week and
 				// truncate to the start of the week to ensure we only get complete weeks
 				// in our output data
-				startDateTime = weekStartDate(startDateTime.Add(7 * 24 * time.Hour))
+				startDateTime = weekStartDate(startDateTime.AddDate(0, 0, 7))
 				fmt.Fprintf(os.Stderr, "WARN: only complete weeks requested, start date set to '%s'\n", startDateTime.Format("2006-01-02"))
 			}
 			endDateTime = refDateTime
 		}
 	}
+	return checkQueryTimeWindow(startDateTime, endDateTime)
+}
+
+/*
+ * a function that checks the start and end times for our query window (exiting with an
+ * error if the window starts in the future and warning the user if it ends in the future)
+ * before returning them as a pair of githubv4.DateTime values
+ */
+func checkQueryTimeWindow(startDateTime time.Time, endDateTime time.Time) (githubv4.DateTime, githubv4.DateTime) {
 	// if the start time for our query window is in the future, we should exit with an error
 	// since no data will be available
-	currentDateTime := time.Now().UTC()
+	currentDateTime := time.Now().In(GetTimeZone())
 	if startDateTime.After(currentDateTime) {
 		fmt.Fprintf(os.Stderr, "ERROR: defined start date for query window is in the future; no data will be available\n")
 		os.Exit(-1)
@@ -211,7 +325,221 @@ func GetQueryTimeWindow() (githubv4.DateTime, githubv4.DateTime) {
 		// if the end time for our query window is in the future, then we should warn the user
 		fmt.Fprintf(os.Stderr, "WARN: defined end date for query window is in the future; results only cover %s through %s\n", startDateTime.Format("2006-01-02"), currentDateTime.Format("2006-01-02"))
 	}
-	fmt.Fprintf(os.Stderr, "INFO: time window for query is %s through %s\n", startDateTime.Format("2006-01-02"), endDateTime.Format("2006-01-02"))
+	fmt.Fprintf(os.Stderr, "INFO: time window for query is %s through %s (%s)\n", startDateTime.Format("2006-01-02"), endDateTime.Format("2006-01-02"), GetTimeZone())
 	// otherwise, return the start and end date times for our query window
-	return githubv4.DateTime{startDateTime}, githubv4.DateTime{endDateTime}
+	return githubv4.DateTime{Time: startDateTime}, githubv4.DateTime{Time: endDateTime}
+}
+
+/*
+ * a function that can be used to parse a date string (of the form 'YYYY-MM-DD') that
+ * was passed in on the command-line using the named flag
+ */
+func parseDate(dateStr string, flagName string) time.Time {
+	dateTime, err := time.ParseInLocation("2006-01-02", dateStr, GetTimeZone())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to parse %s date '%s'; expected format is '2006-01-02'\n", flagName, dateStr)
+		os.Exit(-1)
+	}
+	return dateTime
+}
+
+/*
+ * returns the month that the fiscal year starts in (from the 'fiscal_year_start_month'
+ * configuration value); by default the fiscal year is the same as the calendar year
+ */
+func getFiscalYearStartMonth() time.Month {
+	if !viper.IsSet("fiscal_year_start_month") {
+		return time.January
+	}
+	startMonth := viper.GetInt("fiscal_year_start_month")
+	if startMonth < 1 || startMonth > 12 {
+		fmt.Fprintf(os.Stderr, "ERROR: invalid 'fiscal_year_start_month' value '%v'; expected a value between 1 and 12\n", viper.Get("fiscal_year_start_month"))
+		os.Exit(-1)
+	}
+	return time.Month(startMonth)
+}
+
+/*
+ * returns the date that the fiscal year with the given label starts on; note that
+ * fiscal years are labelled by the calendar year that they end in (so if the fiscal
+ * year starts in February, then fiscal year 2027 runs from February 1st, 2026 through
+ * January 31st, 2027)
+ */
+func getFiscalYearStart(fiscalYear int) time.Time {
+	startMonth := getFiscalYearStartMonth()
+	if startMonth != time.January {
+		fiscalYear--
+	}
+	return time.Date(fiscalYear, startMonth, 1, 0, 0, 0, 0, GetTimeZone())
+}
+
+/*
+ * returns the date that the fiscal quarter containing the input date starts on
+ */
+func getFiscalQuarterStart(date time.Time) time.Time {
+	monthsIntoFiscalYear := (int(date.Month()) - int(getFiscalYearStartMonth()) + 12) % 12
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, GetTimeZone())
+	return monthStart.AddDate(0, -(monthsIntoFiscalYear % 3), 0)
+}
+
+/*
+ * returns the date that the fiscal year containing the input date starts on
+ */
+func getFiscalYearStartContaining(date time.Time) time.Time {
+	monthsIntoFiscalYear := (int(date.Month()) - int(getFiscalYearStartMonth()) + 12) % 12
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, GetTimeZone())
+	return monthStart.AddDate(0, -monthsIntoFiscalYear, 0)
+}
+
+/*
+ * a function that can be used to parse the named "period" that can be passed in on the
+ * command-line and return the start and end of the corresponding time window; supported
+ * periods include:
+ *     - (fiscal) years (e.g. "2026")
+ *     - (fiscal) quarters (e.g. "2026-Q3")
+ *     - calendar months (e.g. "2026-09")
+ *     - ISO 8601 weeks, which start on a Monday (e.g. "2026-W37")
+ *     - periods relative to the current date (e.g. "this-week", "last-month",
+ *       "last-quarter", or "this-year"); here quarters and years are fiscal
+ *       quarters and years
+ *
+ * note that the end of the time window returned is the start of the following period
+ */
+func getPeriodTimeWindow(period string) (time.Time, time.Time) {
+	// define a regular expression to parse the period string
+	parsePattern := `^(?:([0-9]{4})(?:-(?:q([1-4])|([0-9]{2})|w([0-9]{2})))?|(this|last)-(week|month|quarter|year))$`
+	re := regexp.MustCompile(parsePattern)
+	matches := re.FindStringSubmatch(strings.ToLower(period))
+	if matches == nil {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to parse period '%s'; expected 'YYYY', 'YYYY-Qn', 'YYYY-MM', 'YYYY-Www', or '(this|last)-(week|month|quarter|year)'\n", period)
+		os.Exit(-1)
+	}
+	// if this is a relative period, then find the start of the period containing the
+	// current date and (if necessary) shift it back by one period
+	if matches[5] != "" {
+		today := getCurrentDate()
+		var startDateTime, endDateTime time.Time
+		switch matches[6] {
+		case "week":
+			startDateTime = weekStartDate(today)
+			endDateTime = startDateTime.AddDate(0, 0, 7)
+			if matches[5] == "last" {
+				startDateTime, endDateTime = startDateTime.AddDate(0, 0, -7), startDateTime
+			}
+		case "month":
+			startDateTime = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, GetTimeZone())
+			endDateTime = startDateTime.AddDate(0, 1, 0)
+			if matches[5] == "last" {
+				startDateTime, endDateTime = startDateTime.AddDate(0, -1, 0), startDateTime
+			}
+		case "quarter":
+			startDateTime = getFiscalQuarterStart(today)
+			endDateTime = startDateTime.AddDate(0, 3, 0)
+			if matches[5] == "last" {
+				startDateTime, endDateTime = startDateTime.AddDate(0, -3, 0), startDateTime
+			}
+		case "year":
+			startDateTime = getFiscalYearStartContaining(today)
+			endDateTime = startDateTime.AddDate(1, 0, 0)
+			if matches[5] == "last" {
+				startDateTime, endDateTime = startDateTime.AddDate(-1, 0, 0), startDateTime
+			}
+		}
+		return startDateTime, endDateTime
+	}
+	// otherwise, it's an absolute period, so start by parsing the year
+	year, _ := strconv.Atoi(matches[1])
+	switch {
+	case matches[2] != "":
+		// a (fiscal) quarter
+		quarter, _ := strconv.Atoi(matches[2])
+		startDateTime := getFiscalYearStart(year).AddDate(0, 3*(quarter-1), 0)
+		return startDateTime, startDateTime.AddDate(0, 3, 0)
+	case matches[3] != "":
+		// a calendar month
+		month, _ := strconv.Atoi(matches[3])
+		if month < 1 || month > 12 {
+			fmt.Fprintf(os.Stderr, "ERROR: invalid month in period '%s'\n", period)
+			os.Exit(-1)
+		}
+		startDateTime := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, GetTimeZone())
+		return startDateTime, startDateTime.AddDate(0, 1, 0)
+	case matches[4] != "":
+		// an ISO 8601 week; the first week of the year is the one that contains
+		// January 4th, and weeks start on Monday
+		week, _ := strconv.Atoi(matches[4])
+		startDateTime := weekStartDate(time.Date(year, time.January, 4, 0, 0, 0, 0, GetTimeZone())).AddDate(0, 0, 7*(week-1))
+		if isoYear, isoWeek := startDateTime.ISOWeek(); week < 1 || isoYear != year || isoWeek != week {
+			fmt.Fprintf(os.Stderr, "ERROR: invalid week in period '%s'\n", period)
+			os.Exit(-1)
+		}
+		return startDateTime, startDateTime.AddDate(0, 0, 7)
+	}
+	// if we get here, then it's a (fiscal) year
+	startDateTime := getFiscalYearStart(year)
+	return startDateTime, startDateTime.AddDate(1, 0, 0)
+}
+
+/*
+ * a function that can be used to get a time window from the explicit start and end
+ * dates (passed in using the '--since' and '--until' flags) or the named period (passed
+ * in using the '--period' flag) that were passed in on the command-line; note that the
+ * end date passed in using the '--until' flag is included in the time window
+ */
+func getExplicitTimeWindow() (time.Time, time.Time) {
+	var startDateTime time.Time
+	var endDateTime time.Time
+	sinceDate := viper.GetString("sinceDate")
+	untilDate := viper.GetString("untilDate")
+	period := viper.GetString("period")
+	// a named period defines both ends of the time window, so it can't be combined
+	// with an explicit start or end date
+	if period != "" {
+		if sinceDate != "" || untilDate != "" {
+			fmt.Fprintf(os.Stderr, "ERROR: the '--period' flag cannot be combined with the '--since' or '--until' flags\n")
+			os.Exit(-1)
+		}
+		startDateTime, endDateTime = getPeriodTimeWindow(period)
+		if viper.GetBool("completeWeeks") && !strings.Contains(strings.ToLower(period), "w") {
+			fmt.Fprintf(os.Stderr, "WARN: only complete weeks requested, but a named period was specified; ignoring the request\n")
+		}
+		return startDateTime, endDateTime
+	}
+	// if an end date was specified, the time window ends at midnight at the end of that
+	// day; otherwise it ends at midnight at the start of the current day
+	if untilDate != "" {
+		endDateTime = parseDate(untilDate, "until").AddDate(0, 0, 1)
+	} else {
+		endDateTime = getCurrentDate()
+	}
+	// if a start date was specified, the time window starts at midnight at the start of
+	// that day; otherwise we use the default lookback time from the end of the window
+	if sinceDate != "" {
+		startDateTime = parseDate(sinceDate, "since")
+	} else {
+		fmt.Fprintf(os.Stderr, "WARN: no start date specified; using default lookback time of 90 days\n")
+		startDateTime = endDateTime.AddDate(0, 0, -defaultLookbackDays)
+	}
+	if !startDateTime.Before(endDateTime) {
+		fmt.Fprintf(os.Stderr, "ERROR: the start of the time window (%s) must be before the end of the time window (%s)\n",
+			startDateTime.Format("2006-01-02"), endDateTime.Format("2006-01-02"))
+		os.Exit(-1)
+	}
+	// if only complete weeks were requested, shift the start of the window forward and the
+	// end of the window back to the nearest Monday
+	if viper.GetBool("completeWeeks") {
+		if !weekStartDate(startDateTime).Equal(startDateTime) {
+			startDateTime = weekStartDate(startDateTime.AddDate(0, 0, 7))
+			fmt.Fprintf(os.Stderr, "WARN: only complete weeks requested, start date set to '%s'\n", startDateTime.Format("2006-01-02"))
+		}
+		if !weekStartDate(endDateTime).Equal(endDateTime) {
+			endDateTime = weekStartDate(endDateTime)
+			fmt.Fprintf(os.Stderr, "WARN: only complete weeks requested, end date set to '%s'\n", endDateTime.Format("2006-01-02"))
+		}
+		if !startDateTime.Before(endDateTime) {
+			fmt.Fprintf(os.Stderr, "ERROR: the time window does not contain any complete weeks\n")
+			os.Exit(-1)
+		}
+	}
+	return startDateTime, endDateTime
 }
@@ -0,0 +1,110 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// a utility function that returns midnight (UTC) on the input date
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestGetPeriodTimeWindow(t *testing.T) {
+	timeZone = time.UTC
+	defer func() { timeZone = nil }()
+	tests := []struct {
+		period        string
+		fiscalStart   int
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{"2026", 0, utcDate(2026, time.January, 1), utcDate(2027, time.January, 1)},
+		{"2026-Q3", 0, utcDate(2026, time.July, 1), utcDate(2026, time.October, 1)},
+		{"2026-q1", 0, utcDate(2026, time.January, 1), utcDate(2026, time.April, 1)},
+		{"2026-09", 0, utcDate(2026, time.September, 1), utcDate(2026, time.October, 1)},
+		{"2026-12", 0, utcDate(2026, time.December, 1), utcDate(2027, time.January, 1)},
+		// ISO 8601 weeks start on Monday, and week 1 contains January 4th
+		{"2026-W01", 0, utcDate(2025, time.December, 29), utcDate(2026, time.January, 5)},
+		{"2026-W37", 0, utcDate(2026, time.September, 7), utcDate(2026, time.September, 14)},
+		{"2026-W53", 0, utcDate(2026, time.December, 28), utcDate(2027, time.January, 4)},
+		// fiscal years are labelled by the calendar year that they end in
+		{"2027", 2, utcDate(2026, time.February, 1), utcDate(2027, time.February, 1)},
+		{"2027-Q1", 2, utcDate(2026, time.February, 1), utcDate(2026, time.May, 1)},
+		{"2027-Q4", 2, utcDate(2026, time.November, 1), utcDate(2027, time.February, 1)},
+		{"2026-Q2", 10, utcDate(2026, time.January, 1), utcDate(2026, time.April, 1)},
+		// calendar months and ISO weeks ignore the fiscal year
+		{"2026-09", 2, utcDate(2026, time.September, 1), utcDate(2026, time.October, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			if tt.fiscalStart != 0 {
+				viper.Set("fiscal_year_start_month", tt.fiscalStart)
+				defer viper.Set("fiscal_year_start_month", nil)
+			}
+			start, end := getPeriodTimeWindow(tt.period)
+			if !start.Equal(tt.expectedStart) || !end.Equal(tt.expectedEnd) {
+				t.Errorf("getPeriodTimeWindow(%q) = (%v, %v); expected (%v, %v)", tt.period, start, end, tt.expectedStart, tt.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestGetFiscalQuarterAndYearStart(t *testing.T) {
+	timeZone = time.UTC
+	defer func() { timeZone = nil }()
+	tests := []struct {
+		date                 time.Time
+		fiscalStart          int
+		expectedQuarterStart time.Time
+		expectedYearStart    time.Time
+	}{
+		{utcDate(2026, time.August, 15), 0, utcDate(2026, time.July, 1), utcDate(2026, time.January, 1)},
+		{utcDate(2026, time.January, 1), 0, utcDate(2026, time.January, 1), utcDate(2026, time.January, 1)},
+		{utcDate(2026, time.January, 20), 2, utcDate(2025, time.November, 1), utcDate(2025, time.February, 1)},
+		{utcDate(2026, time.February, 1), 2, utcDate(2026, time.February, 1), utcDate(2026, time.February, 1)},
+		{utcDate(2026, time.December, 31), 10, utcDate(2026, time.October, 1), utcDate(2026, time.October, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date.Format("2006-01-02"), func(t *testing.T) {
+			if tt.fiscalStart != 0 {
+				viper.Set("fiscal_year_start_month", tt.fiscalStart)
+				defer viper.Set("fiscal_year_start_month", nil)
+			}
+			if quarterStart := getFiscalQuarterStart(tt.date); !quarterStart.Equal(tt.expectedQuarterStart) {
+				t.Errorf("getFiscalQuarterStart(%v) = %v; expected %v", tt.date, quarterStart, tt.expectedQuarterStart)
+			}
+			if yearStart := getFiscalYearStartContaining(tt.date); !yearStart.Equal(tt.expectedYearStart) {
+				t.Errorf("getFiscalYearStartContaining(%v) = %v; expected %v", tt.date, yearStart, tt.expectedYearStart)
+			}
+		})
+	}
+}
+
+func TestGetPeriodStartDate(t *testing.T) {
+	timeZone = time.UTC
+	defer func() { timeZone = nil }()
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		period    string
+		expected  time.Time
+	}{
+		{"daily", time.Date(2026, time.September, 10, 15, 30, 0, 0, time.UTC), "daily", utcDate(2026, time.September, 10)},
+		{"weekly (Thursday)", time.Date(2026, time.September, 10, 15, 30, 0, 0, time.UTC), "weekly", utcDate(2026, time.September, 7)},
+		{"weekly (Monday)", utcDate(2026, time.September, 7), "weekly", utcDate(2026, time.September, 7)},
+		{"weekly (Sunday)", time.Date(2026, time.September, 13, 23, 59, 0, 0, time.UTC), "weekly", utcDate(2026, time.September, 7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := GetPeriodStartDate(tt.timestamp, tt.period); !actual.Equal(tt.expected) {
+				t.Errorf("GetPeriodStartDate(%v, %q) = %v; expected %v", tt.timestamp, tt.period, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"sort"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -65,7 +65,7 @@ func ReadYamlFile(fileName string) []map[string]interface{} {
 	data := make([]map[interface{}]interface{}, 5)
 	err2 := yaml.Unmarshal(yfile, &data)
 	if err2 != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: while unmarshaling data from input YAML file '%s'; %s\n", fileName, err)
+		fmt.Fprintf(os.Stderr, "ERROR: while unmarshaling data from input YAML file '%s'; %s\n", fileName, err2)
 		os.Exit(-6)
 	}
 	// convert the slice of maps of interfaces to interfaces into a slice of maps of strings
@@ -77,6 +77,43 @@ func ReadYamlFile(fileName string) []map[string]interface{} {
 	return listOfStringMaps
 }
 
+/*
+ * a utility function that returns the input value formatted as a YAML scalar
+ * (quoting it if necessary)
+ */
+func YamlScalar(value string) string {
+	yamlBytes, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%q", value)
+	}
+	return strings.TrimSpace(string(yamlBytes))
+}
+
+/*
+ * a utility function that formats a team member as a (flow-style) YAML map, in the
+ * same '{user, name, githubid}' form used for the members of each team in the
+ * configuration file (along with their 'aliases' and 'emails', if any); the 'name'
+ * is left out if it is empty
+ */
+func TeamMemberAsYAML(member map[string]string) string {
+	fields := []string{"user: " + YamlScalar(member["user"])}
+	if member["name"] != "" {
+		fields = append(fields, "name: "+YamlScalar(member["name"]))
+	}
+	fields = append(fields, "githubid: "+YamlScalar(member["githubid"]))
+	for _, key := range []string{"aliases", "emails"} {
+		if member[key] == "" {
+			continue
+		}
+		values := []string{}
+		for _, value := range strings.Split(member[key], ",") {
+			values = append(values, YamlScalar(value))
+		}
+		fields = append(fields, fmt.Sprintf("%s: [%s]", key, strings.Join(values, ", ")))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
 /*
  * a utility function that can be used to convert a map of interfaces to interfaces to
  * a map of strings to interfaces
@@ -84,7 +121,7 @@ func ReadYamlFile(fileName string) []map[string]interface{} {
 func convInterToInterMapToStringToInterMap(inputMap map[interface{}]interface{}) map[string]interface{} {
 	outputMap := map[string]interface{}{}
 	for key, val := range inputMap {
-		outputMap[key.(string)] = val
+		outputMap[fmt.Sprint(key)] = val
 	}
 	return outputMap
 }
@@ -106,76 +143,11 @@ func DumpMapAsJSON(results interface{}) {
 }
 
 /*
- * a utility function that can be used to return the stats (min, max, median, average
- * first quartile, and third quartile) of a slice of data along with the length of
- * the slice
+ * a function that can be used to dump out the results of the query as plain
+ * text (for output formats other than JSON, like CSV)
  */
-func GetJsonDurationStats(data []time.Duration) (map[string]JsonDuration, int) {
-	sliceLen := len(data)
-	// initialize a variable to hold the results
-	zeroDuration := JsonDuration{time.Duration(0)}
-	results := map[string]JsonDuration{"minimum": zeroDuration,
-		"firstQuartile": zeroDuration, "median": zeroDuration, "average": zeroDuration,
-		"thirdQuartile": zeroDuration, "maximum": zeroDuration}
-	// if the slice is empty, just return the results
-	if sliceLen == 0 {
-		return results, sliceLen
-	}
-	// if we have more than one value, then sort it from least to greatest
-	if sliceLen > 1 {
-		sort.Slice(data, func(i, j int) bool {
-			return data[i] < data[j]
-		})
-	}
-	// grab the two obvious values
-	results["minimum"] = JsonDuration{data[0]}
-	results["maximum"] = JsonDuration{data[sliceLen-1]}
-	results["average"] = JsonDuration{GetAverageDuration(data)}
-	// and grab the remaining stats based on the length of the slice; note that
-	// the first quartile is defined as the median of the lower half of the
-	// slice of durations and the third quartile is the median of the uppoer
-	// half fo that same slice, so the behavior changes a bit depending on
-	// the slice length
-	switch sliceLen {
-	case 1:
-		// only one value; all three (first quartile, median, and third quartile)
-		// are the same
-		results["median"] = JsonDuration{data[0]}
-		results["firstQuartile"] = results["median"]
-		results["thirdQuartile"] = results["median"]
-	case 2:
-		// if two values, the median is the average of those two numbers
-		results["median"] = JsonDuration{(data[0] + data[1]) / 2}
-		fallthrough
-	case 3:
-		// if three values, then the median is the middle value
-		if sliceLen == 3 {
-			results["median"] = JsonDuration{data[1]}
-		}
-		// for either a two or three value slice, the median of lower
-		// half is the first value and the median of the second half is
-		// the last value
-		results["firstQuartile"] = JsonDuration{data[0]}
-		results["thirdQuartile"] = JsonDuration{data[sliceLen-1]}
-	default:
-		// for the rest, we have to know if this is an odd-length slice or
-		// an even-length slice
-		if sliceLen%2 > 0 {
-			// if here, it's a odd length slice so things are easy
-			results["median"] = JsonDuration{data[sliceLen/2]}
-			results["firstQuartile"] = JsonDuration{data[sliceLen/4]}
-			results["thirdQuartile"] = JsonDuration{data[(sliceLen*3)/4]}
-		} else {
-			// if here, it's an even length slice so we have to do a little more work
-			// in this case the values we want are the average of the two values around
-			// where the actual value would be in an odd-length list
-			results["median"] = JsonDuration{(data[sliceLen/2-1] + data[sliceLen/2]) / 2}
-			results["firstQuartile"] = JsonDuration{(data[sliceLen/4-1] + data[sliceLen/4]) / 2}
-			results["thirdQuartile"] = JsonDuration{(data[(sliceLen*3)/4-1] + data[(sliceLen*3)/4]) / 2}
-		}
-	}
-	// finally, return the results
-	return results, sliceLen
+func DumpText(results string) {
+	fmt.Print(results)
 }
 
 /*
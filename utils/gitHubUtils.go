@@ -80,3 +80,152 @@ func GetOrgIdList(client *githubv4.Client) []githubv4.ID {
 	}
 	return orgIdList
 }
+
+/*
+ * define a pair of structs that we'll use to retrieve the members of a team in a given
+ * organization; the first is used to query for the first page of results and the second
+ * is used to query for subsequent pages of results
+ */
+type teamMembersBody struct {
+	Nodes []struct {
+		Login string
+		Name  string
+	}
+	PageInfo struct {
+		EndCursor   githubv4.String
+		HasNextPage bool
+	}
+}
+
+var firstTeamMembersQuery struct {
+	Organization struct {
+		Team struct {
+			Slug    string
+			Members struct {
+				teamMembersBody
+			} `graphql:"members(first: $first, membership: $membership)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+var teamMembersQuery struct {
+	Organization struct {
+		Team struct {
+			Slug    string
+			Members struct {
+				teamMembersBody
+			} `graphql:"members(first: $first, after: $after, membership: $membership)"`
+		} `graphql:"team(slug: $slug)"`
+	} `graphql:"organization(login: $orgname)"`
+}
+
+// the members of the GitHub teams that we've already retrieved, keyed by organization,
+// team slug, and membership type (so that we only need to retrieve each team once)
+var gitHubTeamMembersCache = map[string][]map[string]string{}
+
+/*
+ * a utility function that returns the short name for a user (the first word of their
+ * name, or their login if they haven't defined a name); used to fill in the 'user'
+ * value for team members that are read from GitHub rather than from the configuration
+ */
+func GetShortUserName(name string, login string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return login
+}
+
+/*
+ * a function that returns the members of the named team in the named organization
+ * (in the same {user, name, githubid} form used for the teams defined in the
+ * configuration file); if the immediateOnly flag is set, then members of child
+ * teams who aren't also direct members of the named team are not included (the
+ * members of each team are only retrieved from GitHub once per run)
+ */
+func GetGitHubTeamMembers(client *githubv4.Client, orgName string, teamSlug string, immediateOnly bool) []map[string]string {
+	membership := githubv4.TeamMembershipTypeAll
+	if immediateOnly {
+		membership = githubv4.TeamMembershipTypeImmediate
+	}
+	cacheKey := strings.ToLower(orgName+"/"+teamSlug) + ":" + string(membership)
+	if teamMembers, ok := gitHubTeamMembersCache[cacheKey]; ok {
+		return teamMembers
+	}
+	vars := map[string]interface{}{
+		"orgname":    githubv4.String(orgName),
+		"slug":       githubv4.String(teamSlug),
+		"first":      githubv4.Int(100),
+		"membership": membership,
+	}
+	teamMembers := []map[string]string{}
+	firstPage := true
+	for {
+		var err error
+		if firstPage {
+			err = client.Query(context.Background(), &firstTeamMembersQuery, vars)
+		} else {
+			err = client.Query(context.Background(), &teamMembersQuery, vars)
+		}
+		if err != nil {
+			// Handle error.
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		var slug string
+		var members teamMembersBody
+		if firstPage {
+			slug = firstTeamMembersQuery.Organization.Team.Slug
+			members = firstTeamMembersQuery.Organization.Team.Members.teamMembersBody
+			firstPage = false
+		} else {
+			slug = teamMembersQuery.Organization.Team.Slug
+			members = teamMembersQuery.Organization.Team.Members.teamMembersBody
+		}
+		// if the team wasn't found, then it's an error
+		if slug == "" {
+			fmt.Fprintf(os.Stderr, "ERROR: unable to find a team with the slug '%s' in the '%s' organization\n", teamSlug, orgName)
+			os.Exit(-6)
+		}
+		for _, member := range members.Nodes {
+			teamMembers = append(teamMembers, map[string]string{
+				"user":     GetShortUserName(member.Name, member.Login),
+				"name":     member.Name,
+				"githubid": member.Login,
+			})
+		}
+		// if we've reached the end of the list of members, break out of the loop
+		if !members.PageInfo.HasNextPage {
+			break
+		}
+		// set the "after" field to the "EndCursor" from the pageInfo structure so
+		// we will get the next page of results when we run the query again
+		vars["after"] = members.PageInfo.EndCursor
+	}
+	gitHubTeamMembersCache[cacheKey] = teamMembers
+	return teamMembers
+}
+
+/*
+ * a utility function that parses a team name of the form '@org/slug' (or '@slug',
+ * in which case the first organization in the list of organizations is used) into
+ * an organization name and GitHub team slug; the last value returned is false if
+ * the team name isn't in this form (i.e. if it names a team in the configuration)
+ */
+func ParseGitHubTeamName(teamName string) (string, string, bool) {
+	if !strings.HasPrefix(teamName, "@") {
+		return "", "", false
+	}
+	orgName, teamSlug, found := strings.Cut(strings.TrimPrefix(teamName, "@"), "/")
+	if !found {
+		teamSlug = orgName
+		orgName = ""
+		if orgNameList := GetOrgNameList(); len(orgNameList) > 0 {
+			orgName = orgNameList[0]
+		}
+	}
+	if orgName == "" || teamSlug == "" {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to parse GitHub team name '%s'; expected '@org/slug'\n", teamName)
+		os.Exit(-6)
+	}
+	return orgName, teamSlug, true
+}
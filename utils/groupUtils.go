@@ -0,0 +1,335 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// the name of the group used for repositories that don't have any tags
+const untaggedGroupName = "untagged"
+
+// the tag we're currently gathering results for (when grouping the results by tag);
+// this is only set while the getResultsByTag function is running, and it restricts the
+// repositories returned by the GetTeamRepos function to those with this tag
+var currentTagGroup *string
+
+// the name of the team we're currently gathering results for, along with the names
+// of the teams that are rolled up into it (when gathering results for more than one team);
+// these are only set while the getResultsByTeam function is running, and they replace the
+// team named on the command-line in the values returned by the GetTeamMembers and
+// GetTeamRepos functions
+var (
+	currentTeamName string
+	currentTeams    []string
+)
+
+/*
+ * a utility function that returns the list of values (tags or team names) passed in
+ * (as a comma-separated list) using the named flag
+ */
+func getFlagValueList(key string) []string {
+	valueList := []string{}
+	for _, value := range strings.Split(viper.GetString(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			valueList = append(valueList, value)
+		}
+	}
+	return valueList
+}
+
+/*
+ * a utility function that returns true if a repository with the input tags should be
+ * included, given the tags passed in using the '--repo-tags' flag (the repository must
+ * have at least one of these tags) and the '--exclude-repo-tags' flag (the repository
+ * must not have any of these tags)
+ */
+func repoMatchesTagFilters(repoTags []string) bool {
+	includeTags := getFlagValueList("repoTags")
+	if len(includeTags) > 0 {
+		found := false
+		for _, tag := range includeTags {
+			found = found || SliceContains(repoTags, tag)
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, tag := range getFlagValueList("excludeRepoTags") {
+		if SliceContains(repoTags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+ * a utility function that returns true if a repository with the input tags is part of
+ * the group that we're currently gathering results for (always true if we aren't
+ * grouping the results by tag)
+ */
+func repoMatchesTagGroup(repoTags []string) bool {
+	if currentTagGroup == nil {
+		return true
+	}
+	if *currentTagGroup == untaggedGroupName {
+		return len(repoTags) == 0
+	}
+	return SliceContains(repoTags, *currentTagGroup)
+}
+
+/*
+ * a utility function that returns true if the user asked us to gather results for more
+ * than one team, either by passing in a comma-separated list of teams (using the
+ * '--team' flag) or by using the '--all-teams' flag
+ */
+func IsMultiTeamQuery() bool {
+	return viper.GetBool("allTeams") || strings.Contains(viper.GetString("teamName"), ",")
+}
+
+/*
+ * a utility function that exits with an error if the user asked us to gather results
+ * for more than one team using the named (non-JSON) output format, since those output
+ * formats can only be used for the results from a single team
+ */
+func CheckSingleTeamFormat(format string) {
+	if IsMultiTeamQuery() {
+		fmt.Fprintf(os.Stderr, "ERROR: the '%s' output format can only be used with a single team; use the 'json' format when gathering data for more than one team\n", format)
+		os.Exit(-9)
+	}
+}
+
+/*
+ * a utility function that returns the names of the teams that the user asked us to
+ * gather results for; this is either the comma-separated list of teams passed in using
+ * the '--team' flag or (if the '--all-teams' flag was used) all of the teams defined in
+ * the configuration file, sorted by name
+ */
+func getSelectedTeamNames() []string {
+	if !viper.GetBool("allTeams") {
+		return getFlagValueList("teamName")
+	}
+	if viper.GetString("teamName") != "" {
+		fmt.Fprintf(os.Stderr, "ERROR: the --team and --all-teams flags cannot be used together\n")
+		os.Exit(-3)
+	}
+	teamsMap, ok := viper.Get("teams").(map[string]interface{})
+	if !ok || len(teamsMap) == 0 {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to find the required 'teams' map in the configuration file\n")
+		os.Exit(-5)
+	}
+	teamNames := []string{}
+	for teamName := range teamsMap {
+		teamNames = append(teamNames, teamName)
+	}
+	sort.Strings(teamNames)
+	return teamNames
+}
+
+/*
+ * a utility function that returns the members of the teams that we're currently
+ * gathering results for (see the GetResultsByTeam function), listing each member
+ * (by GitHub ID) only once
+ */
+func getCurrentTeamsMembers() (string, []map[string]string) {
+	teamList := []map[string]string{}
+	gitHubIds := []string{}
+	for _, teamName := range currentTeams {
+		_, teamMembers := GetTeamMembers(teamName)
+		for _, member := range teamMembers {
+			if !SliceContains(gitHubIds, member["githubid"]) {
+				gitHubIds = append(gitHubIds, member["githubid"])
+				teamList = append(teamList, member)
+			}
+		}
+	}
+	return currentTeamName, teamList
+}
+
+/*
+ * a utility function that returns the repositories (and their tags) that are owned by
+ * the teams that we're currently gathering results for (see the GetResultsByTeam function)
+ */
+func getCurrentTeamsReposAndTags() (string, []string, map[string][]string) {
+	teamRepos := []string{}
+	tagsByRepo := map[string][]string{}
+	for _, teamName := range currentTeams {
+		_, repos, tags := getTeamReposAndTags(teamName)
+		for _, repo := range repos {
+			if !SliceContains(teamRepos, repo) {
+				teamRepos = append(teamRepos, repo)
+			}
+			for _, tag := range tags[repo] {
+				if !SliceContains(tagsByRepo[repo], tag) {
+					tagsByRepo[repo] = append(tagsByRepo[repo], tag)
+				}
+			}
+		}
+	}
+	return currentTeamName, teamRepos, tagsByRepo
+}
+
+/*
+ * a utility function that returns true if at least one of the members of the teams
+ * that we're currently gathering results for was on one of those teams at some point
+ * during the query time window
+ */
+func hasActiveMembers() bool {
+	_, teamMembers := GetTeamMembers()
+	startDateTime, endDateTime := GetQueryTimeWindow()
+	for _, member := range teamMembers {
+		if GetMemberActiveDays(member, startDateTime.Time, endDateTime.Time) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * a function that runs the input query function for each of the teams that the user
+ * asked us to gather results for (see the getSelectedTeamNames function), returning the
+ * results for each team under the 'byTeam' key and the results for all of those teams,
+ * taken together, under the 'orgWide' key; the subteams of each team (the children of
+ * that team in the repository mapping file) are rolled up into that team, so the results
+ * for a team include the repositories and members of its subteams. If the user didn't
+ * ask for more than one team, then the query is simply run once and its results returned.
+ * When gathering the results for repositories (the reposNeeded flag is set), teams that
+ * don't own any repositories are skipped if the '--all-teams' flag was used, while teams
+ * that had no (active) members during the query time window are skipped otherwise. Note
+ * that the query is run in full once for each team and once more for all of them, so
+ * gathering the results for N teams costs N+1 times as many GitHub API queries as
+ * gathering them for a single team (and that cost is multiplied again by the number of
+ * tags when the results are also grouped by tag)
+ */
+func getResultsByTeam[R any](getResults func() R, reposNeeded bool) interface{} {
+	if !IsMultiTeamQuery() {
+		return getResults()
+	}
+	if !reposNeeded && viper.GetString("userList") != "" {
+		fmt.Fprintf(os.Stderr, "ERROR: the --user-list flag cannot be used when gathering results for more than one team\n")
+		os.Exit(-3)
+	}
+	selectedTeams := []string{}
+	for _, teamName := range getSelectedTeamNames() {
+		if reposNeeded && viper.GetBool("allTeams") && !hasRepoMapping(teamName) {
+			fmt.Fprintf(os.Stderr, "WARNING: team '%s' has no repositories in the repository mapping file; skipping\n", teamName)
+			continue
+		}
+		selectedTeams = append(selectedTeams, teamName)
+	}
+	// make sure that the team we're gathering results for is reset when we return
+	defer func() {
+		currentTeamName = ""
+		currentTeams = nil
+	}()
+	// gather the results for each team (rolling up its subteams into it)
+	teamNames := []string{}
+	byTeam := map[string]interface{}{}
+	subteams := map[string]interface{}{}
+	rolledUpTeams := []string{}
+	for _, teamName := range selectedTeams {
+		subteamNames := getSubteamNames(teamName)
+		currentTeamName = teamName
+		currentTeams = append([]string{teamName}, subteamNames...)
+		if !reposNeeded && viper.GetString("gitHubIdList") == "" && !hasActiveMembers() {
+			fmt.Fprintf(os.Stderr, "WARNING: team '%s' had no members during the time window; skipping\n", teamName)
+			continue
+		}
+		// only the teams that weren't skipped are included in the org-wide results
+		teamNames = append(teamNames, teamName)
+		for _, rolledUpTeam := range currentTeams {
+			if !SliceContains(rolledUpTeams, rolledUpTeam) {
+				rolledUpTeams = append(rolledUpTeams, rolledUpTeam)
+			}
+		}
+		if len(subteamNames) > 0 {
+			subteams[teamName] = subteamNames
+		}
+		fmt.Fprintf(os.Stderr, "INFO: gathering results for team '%s'\n", teamName)
+		byTeam[teamName] = getResults()
+	}
+	// then gather the results for all of those teams (and their subteams), taken together
+	currentTeamName = strings.Join(teamNames, ",")
+	currentTeams = rolledUpTeams
+	fmt.Fprintf(os.Stderr, "INFO: gathering results for all teams\n")
+	orgWide := getResults()
+	return map[string]interface{}{"teams": teamNames, "subteams": subteams, "byTeam": byTeam, "orgWide": orgWide}
+}
+
+/*
+ * a function that runs the input (user) query function and returns the results; if the
+ * user asked us to gather the results for more than one team, then the query is run once
+ * for each team and once for all of those teams, taken together (see getResultsByTeam)
+ */
+func GetResultsByTeam[R any](getResults func() R) interface{} {
+	return getResultsByTeam(getResults, false)
+}
+
+/*
+ * a function that runs the input (repository) query function and returns the results;
+ * if the user asked us to gather the results for more than one team, then the results
+ * are gathered separately for each team (see getResultsByTeam), and if the user asked
+ * us to group the results by tag, then they are grouped by tag (see getResultsByTag)
+ * within the results for each team
+ */
+func GetResultsByGroup[R any](getResults func() R) interface{} {
+	return getResultsByTeam(func() interface{} {
+		return getResultsByTag(getResults)
+	}, true)
+}
+
+/*
+ * a function that runs the input query function and returns the results; if the user
+ * asked us to group the results by tag (using the '--group-by tag' flag), then the query
+ * is run once for each of the tags found on the repositories owned by the named team
+ * (restricting the repositories to those with that tag each time), with the results for
+ * repositories without any tags gathered under the 'untagged' group
+ */
+func getResultsByTag[R any](getResults func() R) interface{} {
+	groupBy := strings.ToLower(viper.GetString("groupBy"))
+	if groupBy == "" {
+		return getResults()
+	}
+	if groupBy != "tag" {
+		fmt.Fprintf(os.Stderr, "ERROR: unrecognized group-by value '%s'; expected 'tag'\n", viper.GetString("groupBy"))
+		os.Exit(-9)
+	}
+	// first, find the tags for the (filtered) repositories owned by the named team
+	teamName, teamRepos, tagsByRepo := getTeamReposAndTags()
+	tagGroups := []string{}
+	hasUntagged := false
+	for _, repo := range teamRepos {
+		if !repoMatchesTagFilters(tagsByRepo[repo]) {
+			continue
+		}
+		if len(tagsByRepo[repo]) == 0 {
+			hasUntagged = true
+		}
+		for _, tag := range tagsByRepo[repo] {
+			if !SliceContains(tagGroups, tag) {
+				tagGroups = append(tagGroups, tag)
+			}
+		}
+	}
+	sort.Strings(tagGroups)
+	if hasUntagged {
+		tagGroups = append(tagGroups, untaggedGroupName)
+	}
+	// then gather the results for each of those tags (making sure that the tag we're
+	// gathering results for is reset when we return)
+	defer func() { currentTagGroup = nil }()
+	byTag := map[string]interface{}{}
+	for _, tag := range tagGroups {
+		tagGroup := tag
+		currentTagGroup = &tagGroup
+		fmt.Fprintf(os.Stderr, "INFO: gathering results for repositories tagged '%s'\n", tag)
+		byTag[tag] = getResults()
+	}
+	return map[string]interface{}{"team": teamName, "groupBy": "tag", "byTag": byTag}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+/*
+ * each team member in the configuration file describes a person; in addition to their
+ * (primary) 'githubid', a person can have a list of 'aliases' (the other GitHub logins
+ * used by that person, for people with separate personal and work GitHub accounts) and
+ * a list of 'emails' (the email addresses used by that person), for example:
+ *
+ *   - &Brian {user: Brian, name: Brian Vu, githubid: brivu, aliases: [brivu-work], emails: [brian@example.com]}
+ *
+ * the results for all of a person's logins are gathered under their (primary) GitHub ID
+ */
+
+// the people defined in the configuration file (so that we only need to find them once)
+var configPeople []map[string]string
+
+/*
+ * a utility function that returns all of the people defined in the teams in the
+ * configuration file; any malformed entries are skipped here (those are reported
+ * by the 'config validate' command)
+ */
+func getConfigPeople() []map[string]string {
+	if configPeople != nil {
+		return configPeople
+	}
+	configPeople = []map[string]string{}
+	teamsMap, _ := viper.Get("teams").(map[string]interface{})
+	for _, teamMap := range teamsMap {
+		memberList, _ := teamMap.([]interface{})
+		for _, member := range memberList {
+			if memberMap, ok := member.(map[string]interface{}); ok {
+				configPeople = append(configPeople, convertTeamMember(memberMap))
+			}
+		}
+	}
+	return configPeople
+}
+
+/*
+ * a utility function that splits a comma-separated list of values (like the 'aliases'
+ * or 'emails' for a team member) into a list, skipping any empty values
+ */
+func splitMemberList(value string) []string {
+	values := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+/*
+ * returns the list of GitHub logins for the input team member (their 'githubid',
+ * followed by any 'aliases' defined for them)
+ */
+func GetMemberLogins(member map[string]string) []string {
+	logins := []string{}
+	for _, login := range append([]string{member["githubid"]}, splitMemberList(member["aliases"])...) {
+		if login != "" && !SliceContains(logins, login) {
+			logins = append(logins, login)
+		}
+	}
+	return logins
+}
+
+/*
+ * returns the list of (lower-cased) email addresses defined for the input team member
+ */
+func GetMemberEmails(member map[string]string) []string {
+	emails := []string{}
+	for _, email := range splitMemberList(member["emails"]) {
+		emails = append(emails, strings.ToLower(email))
+	}
+	return emails
+}
+
+/*
+ * returns the (primary) GitHub ID of the person that the input GitHub login belongs
+ * to; if that login isn't one of the logins for any of the people defined in the
+ * configuration file, then it is returned unchanged
+ */
+func GetPersonId(login string) string {
+	for _, person := range getConfigPeople() {
+		for _, personLogin := range GetMemberLogins(person) {
+			if strings.EqualFold(login, personLogin) {
+				return person["githubid"]
+			}
+		}
+	}
+	return login
+}
+
+/*
+ * returns all of the GitHub logins for the person with the input (primary) GitHub ID,
+ * starting with that GitHub ID; if that GitHub ID isn't defined for any of the people
+ * in the configuration file, then it is the only login returned
+ */
+func GetUserLogins(gitHubId string) []string {
+	logins := []string{gitHubId}
+	for _, person := range getConfigPeople() {
+		if !strings.EqualFold(person["githubid"], gitHubId) {
+			continue
+		}
+		for _, login := range GetMemberLogins(person) {
+			if !SliceContains(logins, login) {
+				logins = append(logins, login)
+			}
+		}
+	}
+	return logins
+}
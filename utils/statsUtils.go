@@ -0,0 +1,361 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// define the default values used for the configurable parts of our distribution
+// statistics (the percentiles to report, the fraction of values to trim from each
+// end of a series when calculating a trimmed mean, and the edges of the histogram
+// buckets that values are counted in)
+var (
+	defaultPercentiles      = []float64{90, 95, 99}
+	defaultTrimFraction     = 0.1
+	defaultHistogramBuckets = []string{"1d", "1w", "30d", "90d"}
+)
+
+/*
+ * a utility function that can be used to read a list of values from the configuration;
+ * the list can either be defined as a YAML list or as a comma-separated string
+ */
+func GetConfigStringList(key string) []string {
+	valueList := []string{}
+	switch val := viper.Get(key).(type) {
+	case nil:
+		return nil
+	case string:
+		for _, item := range strings.Split(val, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				valueList = append(valueList, item)
+			}
+		}
+	case []interface{}:
+		for _, item := range val {
+			valueList = append(valueList, strings.TrimSpace(fmt.Sprint(item)))
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "ERROR: unable to parse the '%s' configuration value; expected a list\n", key)
+		os.Exit(-9)
+	}
+	return valueList
+}
+
+/*
+ * retrieve the list of percentiles that should be reported (from the 'stats.percentiles'
+ * configuration value or the default list of percentiles if that value isn't defined)
+ */
+func getStatsPercentiles() []float64 {
+	configList := GetConfigStringList("stats.percentiles")
+	if configList == nil {
+		return defaultPercentiles
+	}
+	percentiles := []float64{}
+	for _, item := range configList {
+		percentile, err := strconv.ParseFloat(item, 64)
+		if err != nil || percentile < 0 || percentile > 100 {
+			fmt.Fprintf(os.Stderr, "ERROR: unable to parse percentile '%s'; expected a number between 0 and 100\n", item)
+			os.Exit(-9)
+		}
+		percentiles = append(percentiles, percentile)
+	}
+	return percentiles
+}
+
+/*
+ * retrieve the fraction of values that should be trimmed from each end of a series
+ * when calculating the trimmed mean (from the 'stats.trim_fraction' configuration value)
+ */
+func getStatsTrimFraction() float64 {
+	if !viper.IsSet("stats.trim_fraction") {
+		return defaultTrimFraction
+	}
+	trimFraction := viper.GetFloat64("stats.trim_fraction")
+	if trimFraction < 0 || trimFraction >= 0.5 {
+		fmt.Fprintf(os.Stderr, "ERROR: invalid trim fraction '%v'; expected a value in the range [0, 0.5)\n", trimFraction)
+		os.Exit(-9)
+	}
+	return trimFraction
+}
+
+/*
+ * retrieve the list of edges for the histogram buckets from the 'stats.histogram_buckets'
+ * configuration value; these edges use the same format as the lookback time (e.g. "1d",
+ * "2w", "1q") and must be positive durations, in ascending order
+ */
+func getStatsHistogramEdges() []time.Duration {
+	configList := GetConfigStringList("stats.histogram_buckets")
+	if configList == nil {
+		configList = defaultHistogramBuckets
+	}
+	edges := []time.Duration{}
+	for idx, item := range configList {
+		edge := getLookbackDuration(item)
+		if edge <= 0 || (idx > 0 && edge <= edges[idx-1]) {
+			fmt.Fprintf(os.Stderr, "ERROR: invalid histogram bucket edge '%s'; expected increasing positive durations\n", item)
+			os.Exit(-9)
+		}
+		edges = append(edges, edge)
+	}
+	return edges
+}
+
+/*
+ * a utility function that returns the q-th quantile (where 0 <= q <= 1) of a sorted
+ * slice of values; all of the quantiles we report (including the median and the
+ * quartiles) are calculated the same way, using linear interpolation between the
+ * two closest ranks (Hyndman and Fan's "type 7" method, which is the default used
+ * by R, NumPy, and the PERCENTILE.INC function in most spreadsheets); with this
+ * method the value at rank h = (n - 1) * q is interpolated between the values at
+ * floor(h) and ceil(h), so the minimum and maximum are the 0th and 100th percentiles
+ */
+func getQuantile(sortedData []float64, q float64) float64 {
+	sliceLen := len(sortedData)
+	if sliceLen == 0 {
+		return 0
+	}
+	rank := float64(sliceLen-1) * q
+	lowerIdx := int(math.Floor(rank))
+	upperIdx := int(math.Ceil(rank))
+	return sortedData[lowerIdx] + (rank-float64(lowerIdx))*(sortedData[upperIdx]-sortedData[lowerIdx])
+}
+
+/*
+ * a utility function that returns the mean of a slice of values after trimming the
+ * given fraction of the values from each end of the (sorted) slice
+ */
+func getTrimmedMean(sortedData []float64, trimFraction float64) float64 {
+	numTrimmed := int(math.Floor(float64(len(sortedData)) * trimFraction))
+	trimmedData := sortedData[numTrimmed : len(sortedData)-numTrimmed]
+	var total float64
+	for _, val := range trimmedData {
+		total += val
+	}
+	return total / float64(len(trimmedData))
+}
+
+/*
+ * a utility function that returns the (population) standard deviation for a slice
+ * of values given the mean of those values
+ */
+func getStandardDeviation(data []float64, mean float64) float64 {
+	var sumOfSquares float64
+	for _, val := range data {
+		sumOfSquares += (val - mean) * (val - mean)
+	}
+	return math.Sqrt(sumOfSquares / float64(len(data)))
+}
+
+/*
+ * a utility function that counts the number of values from a sorted slice of values
+ * that fall into each of the buckets defined by the input (sorted) list of edges; the
+ * first bucket contains the values less than the first edge, the last contains the
+ * values greater than or equal to the last edge, and each bucket in between contains
+ * the values greater than or equal to its lower edge and less than its upper edge
+ */
+func getHistogramCounts(sortedData []float64, edges []float64) []int {
+	counts := make([]int, len(edges)+1)
+	bucketIdx := 0
+	for _, val := range sortedData {
+		for bucketIdx < len(edges) && val >= edges[bucketIdx] {
+			bucketIdx++
+		}
+		counts[bucketIdx]++
+	}
+	return counts
+}
+
+/*
+ * a utility function that can be used to return the distribution statistics for a slice
+ * of durations along with the length of the slice; the statistics returned include the
+ * minimum, first quartile, median, average, third quartile, and maximum values, a
+ * configurable set of percentiles (p90, p95, and p99 by default), the standard deviation,
+ * a trimmed mean (trimming 10% of the values from each end of the sorted slice by default),
+ * and a histogram showing the number of values in each of a configurable set of buckets
+ */
+func GetJsonDurationStats(data []time.Duration) (map[string]interface{}, int) {
+	sliceLen := len(data)
+	// first, grab the configurable parts of the statistics we're going to return
+	percentiles := getStatsPercentiles()
+	trimFraction := getStatsTrimFraction()
+	histogramEdges := getStatsHistogramEdges()
+	// then sort the durations from least to greatest and convert them to a slice
+	// of floating point values (in nanoseconds) so that we can use them in our
+	// calculations
+	sort.Slice(data, func(i, j int) bool {
+		return data[i] < data[j]
+	})
+	sortedData := []float64{}
+	for _, duration := range data {
+		sortedData = append(sortedData, float64(duration))
+	}
+	// define a function that we can use to convert the floating point values from
+	// our calculations back to durations (for output)
+	toJsonDuration := func(val float64) JsonDuration {
+		return JsonDuration{time.Duration(math.Round(val))}
+	}
+	// initialize a variable to hold the results (with zero values for all of the
+	// statistics, which is what we'll return if the slice is empty)
+	zeroDuration := JsonDuration{time.Duration(0)}
+	results := map[string]interface{}{"minimum": zeroDuration,
+		"firstQuartile": zeroDuration, "median": zeroDuration, "average": zeroDuration,
+		"thirdQuartile": zeroDuration, "maximum": zeroDuration,
+		"standardDeviation": zeroDuration, "trimmedMean": zeroDuration}
+	for _, percentile := range percentiles {
+		results["p"+strconv.FormatFloat(percentile, 'f', -1, 64)] = zeroDuration
+	}
+	// and calculate the counts for our histogram buckets (which will be all zeros
+	// if the slice is empty)
+	edgeValues := []float64{}
+	for _, edge := range histogramEdges {
+		edgeValues = append(edgeValues, float64(edge))
+	}
+	histogram := []map[string]interface{}{}
+	for idx, count := range getHistogramCounts(sortedData, edgeValues) {
+		bucket := map[string]interface{}{"count": count}
+		if idx > 0 {
+			bucket["from"] = JsonDuration{histogramEdges[idx-1]}
+		}
+		if idx < len(histogramEdges) {
+			bucket["to"] = JsonDuration{histogramEdges[idx]}
+		}
+		histogram = append(histogram, bucket)
+	}
+	results["histogram"] = histogram
+	// if the slice is empty, just return the results
+	if sliceLen == 0 {
+		return results, sliceLen
+	}
+	// otherwise, calculate the remaining statistics
+	average := GetAverageDuration(data)
+	results["minimum"] = JsonDuration{data[0]}
+	results["maximum"] = JsonDuration{data[sliceLen-1]}
+	results["average"] = JsonDuration{average}
+	results["firstQuartile"] = toJsonDuration(getQuantile(sortedData, 0.25))
+	results["median"] = toJsonDuration(getQuantile(sortedData, 0.5))
+	results["thirdQuartile"] = toJsonDuration(getQuantile(sortedData, 0.75))
+	for _, percentile := range percentiles {
+		results["p"+strconv.FormatFloat(percentile, 'f', -1, 64)] = toJsonDuration(getQuantile(sortedData, percentile/100))
+	}
+	results["standardDeviation"] = toJsonDuration(getStandardDeviation(sortedData, float64(average)))
+	results["trimmedMean"] = toJsonDuration(getTrimmedMean(sortedData, trimFraction))
+	// finally, return the results
+	return results, sliceLen
+}
+
+/*
+ * a utility function that returns the median of a slice of durations
+ */
+func GetMedianDuration(data []time.Duration) time.Duration {
+	sortedData := []float64{}
+	for _, duration := range data {
+		sortedData = append(sortedData, float64(duration))
+	}
+	sort.Float64s(sortedData)
+	return time.Duration(math.Round(getQuantile(sortedData, 0.5)))
+}
+
+/*
+ * a utility function that can be used to return the distribution statistics for a slice
+ * of (numeric) values along with the length of the slice; the statistics returned are the
+ * same as those returned by the GetJsonDurationStats function (above), except that no
+ * histogram is included
+ */
+func GetNumericStats(data []float64) (map[string]interface{}, int) {
+	sliceLen := len(data)
+	percentiles := getStatsPercentiles()
+	trimFraction := getStatsTrimFraction()
+	// sort a copy of the values from least to greatest
+	sortedData := append([]float64{}, data...)
+	sort.Float64s(sortedData)
+	// define a function that we can use to round the values from our calculations (for output)
+	round := func(val float64) float64 {
+		return math.Round(val*100) / 100
+	}
+	// initialize a variable to hold the results (with zero values for all of the
+	// statistics, which is what we'll return if the slice is empty)
+	results := map[string]interface{}{"minimum": 0.0, "firstQuartile": 0.0, "median": 0.0,
+		"average": 0.0, "thirdQuartile": 0.0, "maximum": 0.0, "standardDeviation": 0.0,
+		"trimmedMean": 0.0}
+	for _, percentile := range percentiles {
+		results["p"+strconv.FormatFloat(percentile, 'f', -1, 64)] = 0.0
+	}
+	// if the slice is empty, just return the results
+	if sliceLen == 0 {
+		return results, sliceLen
+	}
+	// otherwise, calculate the statistics
+	var total float64
+	for _, val := range sortedData {
+		total += val
+	}
+	average := total / float64(sliceLen)
+	results["minimum"] = sortedData[0]
+	results["maximum"] = sortedData[sliceLen-1]
+	results["average"] = round(average)
+	results["firstQuartile"] = round(getQuantile(sortedData, 0.25))
+	results["median"] = round(getQuantile(sortedData, 0.5))
+	results["thirdQuartile"] = round(getQuantile(sortedData, 0.75))
+	for _, percentile := range percentiles {
+		results["p"+strconv.FormatFloat(percentile, 'f', -1, 64)] = round(getQuantile(sortedData, percentile/100))
+	}
+	results["standardDeviation"] = round(getStandardDeviation(sortedData, average))
+	results["trimmedMean"] = round(getTrimmedMean(sortedData, trimFraction))
+	// finally, return the results
+	return results, sliceLen
+}
+
+/*
+ * a utility function that returns the median of a slice of values
+ */
+func GetMedian(data []float64) float64 {
+	sortedData := append([]float64{}, data...)
+	sort.Float64s(sortedData)
+	return getQuantile(sortedData, 0.5)
+}
+
+/*
+ * a utility function that returns the mean and (population) standard deviation
+ * of a slice of values
+ */
+func GetMeanAndStandardDeviation(data []float64) (float64, float64) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	var total float64
+	for _, val := range data {
+		total += val
+	}
+	mean := total / float64(len(data))
+	return mean, getStandardDeviation(data, mean)
+}
+
+/*
+ * a utility function that returns the percentile rank of the input value within a
+ * slice of values (the percentage of the values that are less than the input value,
+ * counting values equal to the input value as half below and half above it)
+ */
+func GetPercentileRank(data []float64, value float64) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+	var numBelow, numEqual float64
+	for _, val := range data {
+		if val < value {
+			numBelow++
+		} else if val == value {
+			numEqual++
+		}
+	}
+	return (numBelow + numEqual/2) / float64(len(data)) * 100
+}
@@ -0,0 +1,118 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetJsonDurationStatsQuantiles(t *testing.T) {
+	hours := func(vals ...float64) []time.Duration {
+		durations := []time.Duration{}
+		for _, val := range vals {
+			durations = append(durations, time.Duration(val*float64(time.Hour)))
+		}
+		return durations
+	}
+	tests := []struct {
+		name     string
+		data     []time.Duration
+		expected map[string]time.Duration
+		count    int
+	}{
+		{
+			name:  "empty",
+			data:  []time.Duration{},
+			count: 0,
+			expected: map[string]time.Duration{"minimum": 0, "firstQuartile": 0, "median": 0,
+				"thirdQuartile": 0, "maximum": 0, "p90": 0},
+		},
+		{
+			name:  "single value",
+			data:  hours(2),
+			count: 1,
+			expected: map[string]time.Duration{"minimum": 2 * time.Hour, "firstQuartile": 2 * time.Hour,
+				"median": 2 * time.Hour, "thirdQuartile": 2 * time.Hour, "maximum": 2 * time.Hour, "p99": 2 * time.Hour},
+		},
+		{
+			name:  "odd number of values (unsorted)",
+			data:  hours(5, 1, 4, 2, 3),
+			count: 5,
+			expected: map[string]time.Duration{"minimum": time.Hour, "firstQuartile": 2 * time.Hour,
+				"median": 3 * time.Hour, "thirdQuartile": 4 * time.Hour, "maximum": 5 * time.Hour,
+				"p90": 276 * time.Minute, "average": 3 * time.Hour},
+		},
+		{
+			name:  "even number of values (interpolated)",
+			data:  hours(1, 2, 3, 4),
+			count: 4,
+			expected: map[string]time.Duration{"firstQuartile": 105 * time.Minute, "median": 150 * time.Minute,
+				"thirdQuartile": 195 * time.Minute, "p90": 222 * time.Minute, "p95": 231 * time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, count := GetJsonDurationStats(tt.data)
+			if count != tt.count {
+				t.Errorf("count = %d; expected %d", count, tt.count)
+			}
+			for key, expected := range tt.expected {
+				actual, ok := results[key].(JsonDuration)
+				if !ok {
+					t.Fatalf("results[%q] = %v; expected a JsonDuration", key, results[key])
+				}
+				if actual.Duration != expected {
+					t.Errorf("results[%q] = %v; expected %v", key, actual.Duration, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMedian(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []float64
+		expected float64
+	}{
+		{"empty", []float64{}, 0},
+		{"single value", []float64{7}, 7},
+		{"odd number of values (unsorted)", []float64{9, 1, 5}, 5},
+		{"even number of values", []float64{4, 1, 3, 2}, 2.5},
+		{"repeated values", []float64{2, 2, 2, 10}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := GetMedian(tt.data); actual != tt.expected {
+				t.Errorf("GetMedian(%v) = %v; expected %v", tt.data, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetPercentileRank(t *testing.T) {
+	data := []float64{10, 20, 20, 30, 40}
+	tests := []struct {
+		name     string
+		data     []float64
+		value    float64
+		expected float64
+	}{
+		{"empty", []float64{}, 5, 0},
+		{"below every value", data, 5, 0},
+		{"above every value", data, 50, 100},
+		{"equal to the lowest value", data, 10, 10},
+		{"equal to a repeated value", data, 20, 40},
+		{"between two values", data, 25, 60},
+		{"only value", []float64{3}, 3, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := GetPercentileRank(tt.data, tt.value); actual != tt.expected {
+				t.Errorf("GetPercentileRank(%v, %v) = %v; expected %v", tt.data, tt.value, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -75,12 +76,23 @@ func GetUserIdList() []string {
 	} else if idVal != "" {
 		inputIdList := idVal.(string)
 		// if so, split it to get a list of user IDs to retrieve GitHub IDs for (from the
-		// config file)
-		userIdList = strings.Split(inputIdList, ",")
+		// config file); any of the logins for a person can be used here, but the results
+		// are gathered under that person's (primary) GitHub ID
+		for _, gitHubId := range strings.Split(inputIdList, ",") {
+			if personId := GetPersonId(gitHubId); !SliceContains(userIdList, personId) {
+				userIdList = append(userIdList, personId)
+			}
+		}
 	} else {
-		// otherwise, get the list of user IDs from the team (as the default user list)
-		_, teamList := GetTeamMembers()
+		// otherwise, get the list of user IDs from the team (as the default user list),
+		// skipping any members that weren't on the team at any time during our time window
+		teamName, teamList = GetTeamMembers()
+		startDateTime, endDateTime := GetQueryTimeWindow()
 		for _, member := range teamList {
+			if GetMemberActiveDays(member, startDateTime.Time, endDateTime.Time) == 0 {
+				fmt.Fprintf(os.Stderr, "WARNING: user '%s' was not a member of the team '%s' during the time window; skipping\n", member["githubid"], teamName)
+				continue
+			}
 			userIdList = append(userIdList, member["githubid"])
 		}
 	}
@@ -106,10 +118,12 @@ func GetTeamMembers(inputTeamName ...string) (string, []map[string]string) {
 		os.Exit(-3)
 	} else if len(inputTeamName) == 1 {
 		teamName = inputTeamName[0]
+	} else if currentTeams != nil {
+		// if we're gathering the results for more than one team (see the GetResultsByTeam
+		// function), then return the members of the teams we're currently gathering results for
+		return getCurrentTeamsMembers()
 	} else {
-		if val := viper.Get("teamName"); val != nil {
-			teamName = val.(string)
-		}
+		teamName = viper.GetString("teamName")
 	}
 	if teamName == "" {
 		teamName = viper.GetString("default_team")
@@ -118,6 +132,13 @@ func GetTeamMembers(inputTeamName ...string) (string, []map[string]string) {
 			os.Exit(-4)
 		}
 	}
+	// if the team name is of the form '@org/slug', then it names a team in one of our
+	// GitHub organizations (rather than a team in the configuration file), so retrieve
+	// the (direct) members of that team directly from GitHub, just as the 'team sync'
+	// command does by default
+	if orgName, teamSlug, ok := ParseGitHubTeamName(teamName); ok {
+		return teamName, GetGitHubTeamMembers(GetAuthenticatedClient(), orgName, teamSlug, true)
+	}
 	// next, look for that team name under the 'teams' config value
 	teamsMap := viper.Get("teams")
 	if teamsMap == nil {
@@ -126,17 +147,29 @@ func GetTeamMembers(inputTeamName ...string) (string, []map[string]string) {
 	} else {
 		// if found an entry by that name, then construct a new list of maps of strings
 		// strings containing the members of that team
-		teamMap := teamsMap.(map[string]interface{})[teamName]
+		teamsStrMap, ok := teamsMap.(map[string]interface{})
+		if !ok {
+			fmt.Fprintf(os.Stderr, "ERROR: the 'teams' value in the configuration file must be a map of team names to lists of members; use the 'config validate' command for details\n")
+			os.Exit(-5)
+		}
+		teamMap := teamsStrMap[teamName]
 		if teamMap == nil {
 			fmt.Fprintf(os.Stderr, "ERROR: unrecognized team name '%s'\n", teamName)
 			os.Exit(-6)
 		}
+		memberList, ok := teamMap.([]interface{})
+		if !ok {
+			fmt.Fprintf(os.Stderr, "ERROR: the members of team '%s' must be defined as a list; use the 'config validate' command for details\n", teamName)
+			os.Exit(-6)
+		}
 		// construct the list of team members as a list of maps of strings to strings
-		for _, member := range teamMap.([]interface{}) {
-			memberStrMap := map[string]string{}
-			for key, val := range member.(map[string]interface{}) {
-				memberStrMap[key] = val.(string)
+		for idx, member := range memberList {
+			memberMap, ok := member.(map[string]interface{})
+			if !ok {
+				fmt.Fprintf(os.Stderr, "ERROR: member %d of team '%s' must be a map (eg. {user: ..., name: ..., githubid: ...}); use the 'config validate' command for details\n", idx+1, teamName)
+				os.Exit(-6)
 			}
+			memberStrMap := convertTeamMember(memberMap)
 			teamList = append(teamList, memberStrMap)
 		}
 	}
@@ -144,14 +177,110 @@ func GetTeamMembers(inputTeamName ...string) (string, []map[string]string) {
 }
 
 /*
- * get a list of the member GitHub IDs from the intput team members map
+ * a utility function that converts a team member (from the configuration file) into
+ * a map of strings to strings; lists of values (like the 'aliases' and 'emails' for a
+ * member) are converted into comma-separated strings
+ */
+func convertTeamMember(memberMap map[string]interface{}) map[string]string {
+	memberStrMap := map[string]string{}
+	for key, val := range memberMap {
+		// note that unquoted dates (like the 'joined' and 'left' dates for a member)
+		// are parsed as timestamps, so convert those back to dates
+		switch typedVal := val.(type) {
+		case string:
+			memberStrMap[key] = typedVal
+		case time.Time:
+			memberStrMap[key] = typedVal.Format("2006-01-02")
+		case []interface{}:
+			values := []string{}
+			for _, listVal := range typedVal {
+				values = append(values, fmt.Sprint(listVal))
+			}
+			memberStrMap[key] = strings.Join(values, ",")
+		default:
+			memberStrMap[key] = fmt.Sprint(typedVal)
+		}
+	}
+	return memberStrMap
+}
+
+/*
+ * a function that returns the part of the input time window during which the input
+ * team member was a member of the team, based on the (optional) 'joined' and 'left'
+ * dates (YYYY-MM-DD) defined for that member in the configuration file (a member is
+ * on the team from the start of the day they joined until the start of the day they
+ * left); members without these dates are assumed to have been on the team for the
+ * whole time window, and if the member wasn't on the team at all during the time
+ * window, then the end of the window that is returned won't be after the start
+ */
+func GetMemberActiveWindow(member map[string]string, startDateTime time.Time, endDateTime time.Time) (time.Time, time.Time) {
+	activeStart := startDateTime
+	activeEnd := endDateTime
+	if joined := member["joined"]; joined != "" {
+		if joinedDate := parseDate(joined, "joined"); joinedDate.After(activeStart) {
+			activeStart = joinedDate
+		}
+	}
+	if left := member["left"]; left != "" {
+		if leftDate := parseDate(left, "left"); leftDate.Before(activeEnd) {
+			activeEnd = leftDate
+		}
+	}
+	return activeStart, activeEnd
+}
+
+/*
+ * a function that returns the number of days within the input time window that the
+ * input team member was a member of the team (see the GetMemberActiveWindow function)
+ */
+func GetMemberActiveDays(member map[string]string, startDateTime time.Time, endDateTime time.Time) float64 {
+	activeStart, activeEnd := GetMemberActiveWindow(member, startDateTime, endDateTime)
+	if !activeEnd.After(activeStart) {
+		return 0
+	}
+	return activeEnd.Sub(activeStart).Hours() / 24
+}
+
+/*
+ * a function that returns true if the input team member was a member of the team at
+ * the input time (based on the 'joined' and 'left' dates defined for that member)
+ */
+func IsMemberActiveAt(member map[string]string, timestamp time.Time) bool {
+	if joined := member["joined"]; joined != "" && timestamp.Before(parseDate(joined, "joined")) {
+		return false
+	}
+	if left := member["left"]; left != "" && !timestamp.Before(parseDate(left, "left")) {
+		return false
+	}
+	return true
+}
+
+/*
+ * the roster for a team maps each of the (lower-cased) GitHub logins for the members of
+ * that team to the corresponding member, so that we can determine whether or not a given
+ * login belonged to a member of the team at a given time
  */
-func GetTeamMemberIds(teamMembers []map[string]string) []string {
-	var memberLogins []string
+type TeamRoster map[string]map[string]string
+
+/*
+ * a function that constructs the roster for a team from the input team members
+ */
+func GetTeamRoster(teamMembers []map[string]string) TeamRoster {
+	roster := TeamRoster{}
 	for _, member := range teamMembers {
-		memberLogins = append(memberLogins, member["githubid"])
+		for _, login := range GetUserLogins(member["githubid"]) {
+			roster[strings.ToLower(login)] = member
+		}
 	}
-	return memberLogins
+	return roster
+}
+
+/*
+ * returns true if the input login belonged to a member of the team at the input time
+ */
+func (roster TeamRoster) IsMemberAt(login string, timestamp time.Time) bool {
+	member, ok := roster[strings.ToLower(login)]
+	return ok && IsMemberActiveAt(member, timestamp)
 }
 
 /*
@@ -193,29 +322,19 @@ func getTeamRepoMappingList(repoMapping []map[string]interface{}, teamName strin
 		if groupMap["group"] == teamName {
 			// if we found the team name, then return the list of all of the repository
 			// mappings that fall under this part of the tree
-			for _, repo := range groupMap["repositories"].([]interface{}) {
-				repoMappingList = append(repoMappingList, convInterToInterMapToStringToInterMap(repo.(map[interface{}]interface{})))
-			}
+			repoMappingList = append(repoMappingList, getRepoMappingEntries(groupMap["repositories"], "repositories", teamName)...)
 			// including the repository mappings for any children of this team
-			if groupMap["children"] != nil {
-				for _, childMap := range groupMap["children"].([]interface{}) {
-					mapAsStringMap := convInterToInterMapToStringToInterMap(childMap.(map[interface{}]interface{}))
-					for key, val := range mapAsStringMap {
-						childTeam := ""
-						if key == "group" {
-							childTeam = val.(string)
-						}
-						// if there is no team name associated with this child, then skip it
-						if childTeam == "" {
-							continue
-						}
-						// and recursively call this function to get the list of repositories
-						// mappings for this child group as well
-						tmpListOfMaps := []map[string]interface{}{mapAsStringMap}
-						subTeamRepoMapping := getTeamRepoMappingList(tmpListOfMaps, childTeam)
-						repoMappingList = append(repoMappingList, subTeamRepoMapping...)
-					}
+			for _, childMap := range getRepoMappingEntries(groupMap["children"], "children", teamName) {
+				childTeam, _ := childMap["group"].(string)
+				// if there is no team name associated with this child, then skip it
+				if childTeam == "" {
+					continue
 				}
+				// and recursively call this function to get the list of repositories
+				// mappings for this child group as well
+				tmpListOfMaps := []map[string]interface{}{childMap}
+				subTeamRepoMapping := getTeamRepoMappingList(tmpListOfMaps, childTeam)
+				repoMappingList = append(repoMappingList, subTeamRepoMapping...)
 			}
 			// and return the resulting list of repository mappings
 			if len(repoMappingList) > 0 {
@@ -223,10 +342,7 @@ func getTeamRepoMappingList(repoMapping []map[string]interface{}, teamName strin
 			}
 		} else if groupMap["children"] != nil {
 			// if the team name for this group doesn't match, then look for it in the children of this group
-			tmpChildren := []map[string]interface{}{}
-			for _, childMap := range groupMap["children"].([]interface{}) {
-				tmpChildren = append(tmpChildren, convInterToInterMapToStringToInterMap(childMap.(map[interface{}]interface{})))
-			}
+			tmpChildren := getRepoMappingEntries(groupMap["children"], "children", fmt.Sprint(groupMap["group"]))
 			teamRepoMappingList := getTeamRepoMappingList(tmpChildren, teamName)
 			if len(teamRepoMappingList) > 0 {
 				// if we found more entries, add them to our list
@@ -241,10 +357,165 @@ func getTeamRepoMappingList(repoMapping []map[string]interface{}, teamName strin
 }
 
 /*
- * a function that can be used to retrieve the list of repositories that are
- * owned by a given team
+ * a utility function that converts the list of entries under the named key (the
+ * 'repositories' or 'children' of a group) in the repository mapping file into a
+ * list of maps of strings to interfaces, exiting with an error if that value isn't
+ * a list of maps (a missing value is treated as an empty list)
  */
-func GetTeamRepos(inputTeamName ...string) (string, []string) {
+func getRepoMappingEntries(val interface{}, key string, groupName string) []map[string]interface{} {
+	entries := []map[string]interface{}{}
+	if val == nil {
+		return entries
+	}
+	entryList, ok := val.([]interface{})
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ERROR: the '%s' for group '%s' in the repository mapping file must be a list; use the 'config validate' command for details\n", key, groupName)
+		os.Exit(-8)
+	}
+	for idx, entry := range entryList {
+		entryMap, ok := entry.(map[interface{}]interface{})
+		if !ok {
+			fmt.Fprintf(os.Stderr, "ERROR: entry %d of the '%s' for group '%s' in the repository mapping file must be a map; use the 'config validate' command for details\n", idx+1, key, groupName)
+			os.Exit(-8)
+		}
+		entries = append(entries, convInterToInterMapToStringToInterMap(entryMap))
+	}
+	return entries
+}
+
+/*
+ * a utility function that returns the name of the repository mapping file that was
+ * either passed in on the command-line or, if it wasn't, the default repository mapping
+ * file defined in the configuration file (an empty string is returned if neither was
+ * defined)
+ */
+func GetRepoMappingFileName() string {
+	if repoMappingFile := viper.GetString("repoMappingFile"); repoMappingFile != "" {
+		return repoMappingFile
+	}
+	return viper.GetString("default_repo_mapping")
+}
+
+/*
+ * a utility function that returns the group for the named team from the repository
+ * mapping file, searching the children of each group recursively (nil is returned if
+ * there is no group for that team in the file)
+ */
+func findRepoMappingGroup(repoMapping []map[string]interface{}, teamName string) map[string]interface{} {
+	for _, group := range repoMapping {
+		if group["group"] == teamName {
+			return group
+		}
+		children := getRepoMappingEntries(group["children"], "children", fmt.Sprint(group["group"]))
+		if childGroup := findRepoMappingGroup(children, teamName); childGroup != nil {
+			return childGroup
+		}
+	}
+	return nil
+}
+
+/*
+ * a utility function that returns the names of the subteams of the named team (the
+ * groups found under the 'children' of that team's group in the repository mapping file,
+ * at any depth), skipping any that aren't also defined as teams in the configuration file;
+ * if there is no repository mapping file (or the named team isn't found in it), then the
+ * team has no subteams
+ */
+func getSubteamNames(teamName string) []string {
+	subteamNames := []string{}
+	repoMappingFile := GetRepoMappingFileName()
+	if repoMappingFile == "" {
+		return subteamNames
+	}
+	if _, err := os.Stat(repoMappingFile); err != nil {
+		return subteamNames
+	}
+	group := findRepoMappingGroup(ReadYamlFile(repoMappingFile), teamName)
+	if group == nil {
+		return subteamNames
+	}
+	teamsMap, _ := viper.Get("teams").(map[string]interface{})
+	children := getRepoMappingEntries(group["children"], "children", teamName)
+	for len(children) > 0 {
+		child := children[0]
+		children = children[1:]
+		childTeam, _ := child["group"].(string)
+		if _, ok := teamsMap[childTeam]; ok && !SliceContains(subteamNames, childTeam) {
+			subteamNames = append(subteamNames, childTeam)
+		}
+		children = append(children, getRepoMappingEntries(child["children"], "children", childTeam)...)
+	}
+	return subteamNames
+}
+
+/*
+ * a utility function that returns true if the named team has a group in the repository
+ * mapping file (or if we aren't using that file to find the repositories owned by each
+ * team, in which case any team can own repositories)
+ */
+func hasRepoMapping(teamName string) bool {
+	if getOwnershipSource() != "mapping" {
+		return true
+	}
+	repoMappingFile := GetRepoMappingFileName()
+	if repoMappingFile == "" {
+		return false
+	}
+	return getTeamRepoMappingList(ReadYamlFile(repoMappingFile), teamName) != nil
+}
+
+/*
+ * a utility function that returns the list of repositories that are owned by the named
+ * team (or one of its subteams) according to the repository mapping file, along with a
+ * map of those repositories to the tags defined for them in that file
+ */
+func getMappedTeamRepos(teamName string) ([]string, map[string][]string) {
+	// first, retrieve the mapping of teams to repositories that was either
+	// passed in on the command-line or read from the configuration file
+	repoMappingFile := GetRepoMappingFileName()
+	if repoMappingFile == "" {
+		// if we didn't find it, then exit with an error
+		fmt.Fprintf(os.Stderr, "ERROR: unable to find the required 'repoMapping' filename\n")
+		os.Exit(-7)
+	}
+	// read the repo mapping file into a map of strings to interfaces
+	teamToRepoMap := ReadYamlFile(repoMappingFile)
+	// and extract the list of repositories that are owned by that team from the map
+	teamRepoMapping := getTeamRepoMappingList(teamToRepoMap, teamName)
+	if teamRepoMapping == nil {
+		fmt.Fprintf(os.Stderr, "ERROR: unrecognized team name '%s'; could not retrieve repository mappings\n", teamName)
+		os.Exit(-8)
+	}
+	// flatten out the resulting mappings to get a list of repositories "managed" by this team
+	// or one of its subteams
+	teamRepos := []string{}
+	tagsByRepo := map[string][]string{}
+	for _, entry := range teamRepoMapping {
+		url, ok := entry["url"].(string)
+		if !ok || len(strings.Split(url, "/")) < 2 {
+			fmt.Fprintf(os.Stderr, "ERROR: invalid repository URL '%v' in the mappings for team '%s'; use the 'config validate' command for details\n", entry["url"], teamName)
+			os.Exit(-8)
+		}
+		splitString := strings.Split(url, "/")
+		repo := strings.Join(splitString[len(splitString)-2:], "/")
+		teamRepos = append(teamRepos, repo)
+		if tags, ok := entry["tags"].([]interface{}); ok {
+			for _, tag := range tags {
+				if !SliceContains(tagsByRepo[repo], fmt.Sprint(tag)) {
+					tagsByRepo[repo] = append(tagsByRepo[repo], fmt.Sprint(tag))
+				}
+			}
+		}
+	}
+	return teamRepos, tagsByRepo
+}
+
+/*
+ * a utility function that returns the list of repositories that are owned by a given
+ * team (before any tag filters are applied), along with a map of those repositories to
+ * their tags (repositories that are only found using the CODEOWNERS files have no tags)
+ */
+func getTeamReposAndTags(inputTeamName ...string) (string, []string, map[string][]string) {
 	// first, get the name of the team we're looking for
 	teamName := ""
 	if len(inputTeamName) > 1 {
@@ -252,10 +523,13 @@ func GetTeamRepos(inputTeamName ...string) (string, []string) {
 		os.Exit(-3)
 	} else if len(inputTeamName) == 1 {
 		teamName = inputTeamName[0]
+	} else if currentTeams != nil {
+		// if we're gathering the results for more than one team (see the GetResultsByTeam
+		// function), then return the repositories for the teams we're currently gathering
+		// results for
+		return getCurrentTeamsReposAndTags()
 	} else {
-		if val := viper.Get("teamName"); val != nil {
-			teamName = val.(string)
-		}
+		teamName = viper.GetString("teamName")
 	}
 	// if we didn't find a team, use the default team name from the configuration (if it exists)
 	if teamName == "" {
@@ -265,34 +539,40 @@ func GetTeamRepos(inputTeamName ...string) (string, []string) {
 			os.Exit(-4)
 		}
 	}
-	// next, retrieve the mapping of teams to repositories that was either
-	// passed in on the command-line or read from the configuration file
-	repoMappingFile := viper.Get("repoMappingFile")
-	if repoMappingFile == nil || repoMappingFile == "" {
-		// if the repo mapping wasn't passed in on the command-line, and wasn't
-		// included in the configuration file, then use the default repo mapping
-		// (if it exists)
-		repoMappingFile = viper.GetString("default_repo_mapping")
-		if repoMappingFile == "" {
-			// if we still didn't find it, then exit with an error
-			fmt.Fprintf(os.Stderr, "ERROR: unable to find the required 'repoMapping' filename\n")
-			os.Exit(-7)
-		}
+	// determine where we should look to find the repositories owned by this team (the
+	// repository mapping file, the CODEOWNERS files in the repositories, or both)
+	ownershipSource := getOwnershipSource()
+	teamRepos := []string{}
+	tagsByRepo := map[string][]string{}
+	if ownershipSource != "codeowners" {
+		teamRepos, tagsByRepo = getMappedTeamRepos(teamName)
 	}
-	// read the repo mapping file into a map of strings to interfaces
-	teamToRepoMap := ReadYamlFile(repoMappingFile.(string))
-	// and extract the list of repositories that are owned by that team from the map
-	teamRepoMapping := getTeamRepoMappingList(teamToRepoMap, teamName)
-	if teamRepoMapping == nil {
-		fmt.Fprintf(os.Stderr, "ERROR: unrecognized team name '%s'; could not retrieve repository mappings\n", teamName)
-		os.Exit(-8)
+	// if we're using the CODEOWNERS files, then add any repositories that are owned by
+	// this team (according to those files) that aren't already in our list
+	if ownershipSource != "mapping" {
+		for _, repo := range GetCodeownersRepos(teamName) {
+			if !SliceContains(teamRepos, repo) {
+				teamRepos = append(teamRepos, repo)
+			}
+		}
 	}
-	// flatten out the resulting mappings to get a list of repositories "managed" by this team
-	// or one of its subteams
-	teamRepos := []string{}
-	for _, entry := range teamRepoMapping {
-		splitString := strings.Split(entry["url"].(string), "/")
-		teamRepos = append(teamRepos, strings.Join(splitString[len(splitString)-2:], "/"))
+	return teamName, teamRepos, tagsByRepo
+}
+
+/*
+ * a function that can be used to retrieve the list of repositories that are
+ * owned by a given team; if the user asked us to include (or exclude) only the
+ * repositories with certain tags (using the '--repo-tags' and '--exclude-repo-tags'
+ * flags), or if we're currently gathering the results for a single tag (see the
+ * GetResultsByGroup function), then only the matching repositories are returned
+ */
+func GetTeamRepos(inputTeamName ...string) (string, []string) {
+	teamName, teamRepos, tagsByRepo := getTeamReposAndTags(inputTeamName...)
+	filteredRepos := []string{}
+	for _, repo := range teamRepos {
+		if repoMatchesTagFilters(tagsByRepo[repo]) && repoMatchesTagGroup(tagsByRepo[repo]) {
+			filteredRepos = append(filteredRepos, repo)
+		}
 	}
-	return teamName, teamRepos
+	return teamName, filteredRepos
 }
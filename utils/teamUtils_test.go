@@ -0,0 +1,70 @@
+/*
+Copyright © 2023 NAME HERE <EMAIL ADDRESS>
+*/
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetMemberActiveWindow(t *testing.T) {
+	timeZone = time.UTC
+	defer func() { timeZone = nil }()
+	windowStart, windowEnd := utcDate(2026, time.July, 1), utcDate(2026, time.October, 1)
+	tests := []struct {
+		name          string
+		member        map[string]string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedDays  float64
+	}{
+		{"no joined or left dates", map[string]string{}, windowStart, windowEnd, 92},
+		{"joined during the window", map[string]string{"joined": "2026-08-01"}, utcDate(2026, time.August, 1), windowEnd, 61},
+		{"left during the window", map[string]string{"left": "2026-07-11"}, windowStart, utcDate(2026, time.July, 11), 10},
+		{"joined and left during the window", map[string]string{"joined": "2026-08-01", "left": "2026-08-15"},
+			utcDate(2026, time.August, 1), utcDate(2026, time.August, 15), 14},
+		{"joined before and left after the window", map[string]string{"joined": "2025-01-01", "left": "2027-01-01"},
+			windowStart, windowEnd, 92},
+		{"joined after the window", map[string]string{"joined": "2026-11-01"}, utcDate(2026, time.November, 1), windowEnd, 0},
+		{"left before the window", map[string]string{"left": "2026-06-01"}, windowStart, utcDate(2026, time.June, 1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := GetMemberActiveWindow(tt.member, windowStart, windowEnd)
+			if !start.Equal(tt.expectedStart) || !end.Equal(tt.expectedEnd) {
+				t.Errorf("GetMemberActiveWindow(%v) = (%v, %v); expected (%v, %v)", tt.member, start, end, tt.expectedStart, tt.expectedEnd)
+			}
+			if days := GetMemberActiveDays(tt.member, windowStart, windowEnd); days != tt.expectedDays {
+				t.Errorf("GetMemberActiveDays(%v) = %v; expected %v", tt.member, days, tt.expectedDays)
+			}
+		})
+	}
+}
+
+func TestIsMemberActiveAt(t *testing.T) {
+	timeZone = time.UTC
+	defer func() { timeZone = nil }()
+	member := map[string]string{"joined": "2026-08-01", "left": "2026-09-01"}
+	tests := []struct {
+		name      string
+		member    map[string]string
+		timestamp time.Time
+		expected  bool
+	}{
+		{"no joined or left dates", map[string]string{}, utcDate(2020, time.January, 1), true},
+		{"before joining", member, time.Date(2026, time.July, 31, 23, 59, 0, 0, time.UTC), false},
+		{"on the day they joined", member, utcDate(2026, time.August, 1), true},
+		{"while on the team", member, time.Date(2026, time.August, 15, 12, 0, 0, 0, time.UTC), true},
+		{"on the day they left", member, utcDate(2026, time.September, 1), false},
+		{"after leaving", member, utcDate(2026, time.October, 1), false},
+		{"only a left date", map[string]string{"left": "2026-09-01"}, utcDate(2026, time.January, 1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := IsMemberActiveAt(tt.member, tt.timestamp); actual != tt.expected {
+				t.Errorf("IsMemberActiveAt(%v, %v) = %v; expected %v", tt.member, tt.timestamp, actual, tt.expected)
+			}
+		})
+	}
+}